@@ -4,14 +4,18 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
-	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/joshwrn/jira-branch/internal/automation"
+	"github.com/joshwrn/jira-branch/internal/cache"
 	"github.com/joshwrn/jira-branch/internal/gui"
 	"github.com/joshwrn/jira-branch/internal/jira"
+	"github.com/joshwrn/jira-branch/internal/utils"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -19,20 +23,31 @@ import (
 type errMsg error
 
 func (m model) Init() tea.Cmd {
-	return tea.Batch(
+	cmds := []tea.Cmd{
 		m.spinner.Tick,
 		textinput.Blink,
+		tickClock(m),
 		func() tea.Msg {
 			credentials, err := jira.LoadCredentials()
 			if err != nil {
 				return credentialsNeededMsg{}
 			}
-			if err := jira.ValidateCredentials(credentials); err != nil {
-				return credentialsNeededMsg{}
-			}
 			return credentials
 		},
-	)
+	}
+
+	if len(m.startupWarnings) > 0 {
+		warning := m.startupWarnings[0]
+		cmds = append(cmds, func() tea.Msg {
+			return toastMsg{text: warning, isError: true}
+		})
+	}
+
+	if interval := m.config.AutoRefreshInterval(); interval > 0 {
+		cmds = append(cmds, scheduleAutoRefresh(interval))
+	}
+
+	return tea.Batch(cmds...)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -42,30 +57,114 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case jira.Credentials:
 		m.credentials = msg
-		m.isLoggedIn = true
-		m.isLoading = true
+		m.client = jira.NewClient(msg)
 		m.view = "list"
-		return m, func() tea.Msg {
-			tickets, err := jira.GetJiraTickets(msg)
-			return ticketsMsg{
-				tickets:                   tickets,
-				err:                       err,
-				shouldOverwriteAllTickets: true,
-			}
+		m.isLoggedIn = true
+		m.loadSort()
+		if entry, ok := cache.Load(m.ticketsCacheKey()); ok {
+			m.allTickets = entry.Tickets
+			m.tickets = entry.Tickets
+			m.lastUpdated = entry.FetchedAt
+			m.isStale = true
+			m.isLoading = false
+			filterTickets(&m)
+			m.logStartup("cache")
+		} else {
+			m.isLoading = true
+		}
+		m.isRefreshing = false
+		m, cmd = m.refreshTickets()
+		return m, tea.Batch(cmd, loadOutbox(), m.restartClock())
+
+	case autoRefreshMsg:
+		interval := m.config.AutoRefreshInterval()
+		if m.isLoggedIn && !m.isLoading && m.err == nil {
+			m, cmd = m.refreshTickets()
+		}
+		return m, tea.Batch(cmd, scheduleAutoRefresh(interval))
+
+	case clockMsg:
+		if msg.id != m.clockID {
+			return m, nil
+		}
+		return m, tickClock(m)
+
+	case toastMsg:
+		return showToast(m, msg)
+
+	case toastExpiredMsg:
+		if msg.id == m.toastID {
+			m.toast = toastMsg{}
+		}
+		return m, nil
+
+	case ticketsMsg:
+		return updateTickets(m, msg)
+
+	case credentialsNeededMsg:
+		return showCredentials(m)
+
+	case switchedBranchMsg:
+		return updateSwitchedBranch(m, msg)
+
+	case deletedBranchMsg:
+		return updateDeletedBranch(m, msg)
+
+	case recentMsg:
+		m.recentEntries = msg
+		m.updateRecentTable()
+		return m, nil
+
+	case previewDebounceMsg:
+		return updatePreviewDebounce(m, msg)
+
+	case previewMsg:
+		return updatePreview(m, msg)
+
+	case jqlHistoryMsg:
+		m.jqlHistory = msg
+		return m, nil
+
+	case outboxMsg:
+		m.outboxOperations = msg.operations
+		m.queuedOperations = len(msg.operations)
+		if msg.status != "" || msg.err != nil {
+			m.outboxStatus = msg.status
+			m.outboxErr = msg.err
+		}
+		m.updateOutboxTable()
+		return m, nil
+
+	case timeMsg:
+		m.timeTotals = msg.totals
+		if msg.status != "" || msg.err != nil {
+			m.timeStatus = msg.status
+			m.timeErr = msg.err
 		}
+		m.updateTimeTable()
+		return m, nil
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		if !m.isLoading && m.err == nil && m.isLoggedIn {
 			m.updateTableSize()
+			m.updateOutboxTable()
+			m.updateTimeTable()
+			m.updateRecentTable()
 		}
 
 	case tea.KeyMsg:
-		if m.isLoggedIn && msg.String() == "q" && m.view == "list" {
+		if m.showHelp && !key.Matches(msg, m.keys.ForceQuit) {
+			m.showHelp = false
+			return m, nil
+		}
+		// q is just a letter while a search input has focus
+		typing := m.showSearch || m.showJQLSearch
+		if m.isLoggedIn && key.Matches(msg, m.keys.Quit) && m.view == "list" && !typing {
 			return m, tea.Quit
 		}
-		if msg.String() == "ctrl+c" {
+		if key.Matches(msg, m.keys.ForceQuit) {
 			return m, tea.Quit
 		}
 
@@ -81,7 +180,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case spinner.TickMsg:
-		if m.isLoading || m.isSubmittingForm {
+		if m.isLoading || m.isSubmittingForm || m.isRefreshing || m.pendingJQL != "" || m.isRunningBatch ||
+			m.isPreparingPR || m.isCreatingPR {
 			m.spinner, cmd = m.spinner.Update(msg)
 			return m, cmd
 		}
@@ -92,6 +192,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case "list":
 		if m.showSearch {
 			return updateSearch(m, msg)
+		} else if m.showJQLSearch {
+			return updateJQLSearch(m, msg)
+		} else if m.tab == "recent" {
+			return updateRecent(m, msg)
 		} else {
 			return updateList(m, msg)
 		}
@@ -99,6 +203,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return updateCredentials(m, msg)
 	case "form":
 		return updateForm(m, msg)
+	case "outbox":
+		return updateOutbox(m, msg)
+	case "batch":
+		return updateBatch(m, msg)
+	case "time":
+		return updateTime(m, msg)
+	case "pr":
+		return updatePullRequest(m, msg)
 	}
 
 	return m, cmd
@@ -110,19 +222,12 @@ func (m model) View() string {
 	}
 
 	if m.err != nil {
-		b := strings.Builder{}
-		bw := b.WriteString
-		bw(gui.ErrorText.Render(fmt.Sprintf("❌ %v", m.err)))
-		bw("\n\n")
-		bw(gui.CreateHelpItems([]gui.HelpItem{
-			{Key: "q/ctrl+c", Desc: "Quit"},
-		}))
-		return b.String()
+		return viewError(m)
 	}
 
 	if !m.isLoggedIn && m.isLoading {
 		return gui.CreateLoadingView(&gui.LoadingView{
-			Text:    "Validating credentials...",
+			Text:    "Loading credentials...",
 			Width:   m.width,
 			Height:  m.height,
 			Spinner: m.spinner,
@@ -138,20 +243,90 @@ func (m model) View() string {
 		})
 	}
 
+	if m.showHelp {
+		return viewHelp(m)
+	}
+
 	if m.view == "form" {
 		return viewForm(m)
 	}
 
+	if m.view == "outbox" {
+		return viewOutbox(m)
+	}
+
+	if m.view == "batch" {
+		return viewBatch(m)
+	}
+
+	if m.view == "time" {
+		return viewTime(m)
+	}
+
+	if m.view == "pr" {
+		return viewPullRequest(m)
+	}
+
 	return viewList(m)
 }
 
+// logStartup records how long it took until the first tickets were on screen.
+func (m *model) logStartup(source string) {
+	if m.startedAt.IsZero() {
+		return
+	}
+	utils.Log.Info().
+		Str("source", source).
+		Dur("duration", time.Since(m.startedAt)).
+		Msg("Startup complete, tickets visible")
+	m.startedAt = time.Time{}
+}
+
 func Run() {
+	config, err := utils.ReadConfigFile()
+	if err != nil {
+		utils.Log.Info().Err(err).Msg("Failed to read config file")
+	}
+
+	startupWarnings := []string{}
+	if !gui.SetTheme(config.Theme) {
+		startupWarnings = append(startupWarnings, fmt.Sprintf(
+			"Unknown theme %q, try one of %s", config.Theme, strings.Join(gui.ThemeNames(), ", "),
+		))
+	}
+
+	keys, keyWarnings := loadKeyMap(config.Keys)
+	for _, warning := range keyWarnings {
+		startupWarnings = append(startupWarnings, fmt.Sprintf("Ignoring key config: %s", warning))
+	}
+	if err := config.ValidateRefreshInterval(); err != nil {
+		startupWarnings = append(startupWarnings, fmt.Sprintf("Auto refresh is off: %s", err))
+	}
+	for _, problem := range automation.Validate(config.Automation) {
+		startupWarnings = append(startupWarnings, fmt.Sprintf("Ignoring automation: %s", problem))
+	}
+	for _, warning := range startupWarnings {
+		utils.Log.Error().Msg(warning)
+	}
+
 	s := spinner.New()
 	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("5"))
+	s.Style = lipgloss.NewStyle().Foreground(gui.CurrentTheme.Accent)
+
+	list := createTicketTable()
+	list.KeyMap = keys.tableKeyMap()
+	recentTable := createTicketTable()
+	recentTable.KeyMap = keys.tableKeyMap()
 
 	m := model{
-		list:             table.New(),
+		startedAt:        time.Now(),
+		config:           config,
+		keys:             keys,
+		startupWarnings:  startupWarnings,
+		columns:          createColumns(config),
+		list:             list,
+		recentTable:      recentTable,
+		tab:              "tickets",
 		spinner:          s,
 		isLoading:        true,
 		isLoggedIn:       false,
@@ -164,7 +339,11 @@ func Run() {
 		search:           "",
 	}
 
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	options := []tea.ProgramOption{tea.WithAltScreen()}
+	if config.MouseEnabled() {
+		options = append(options, tea.WithMouseCellMotion())
+	}
+	p := tea.NewProgram(m, options...)
 
 	if _, err := p.Run(); err != nil {
 		fmt.Println("Error running program:", err)
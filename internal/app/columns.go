@@ -0,0 +1,204 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/joshwrn/jira-branch/internal/jira"
+	"github.com/joshwrn/jira-branch/internal/query"
+	"github.com/joshwrn/jira-branch/internal/utils"
+)
+
+type columnKind int
+
+const (
+	textColumn columnKind = iota
+	keyColumn
+	numberColumn
+	// shown relative to now
+	timeColumn
+	// shown as is, like the due date
+	dateColumn
+)
+
+type ticketColumn struct {
+	id    string
+	title string
+	kind  columnKind
+	// the Jira field to fetch for the column
+	field    string
+	minWidth int
+	// share of the width left over after every column got its minimum
+	weight int
+	value  func(ticket jira.JiraTicketsMsg) string
+}
+
+func (c ticketColumn) text(ticket jira.JiraTicketsMsg) string {
+	if c.kind == timeColumn {
+		value := c.value(ticket)
+		if value == "" {
+			return ""
+		}
+		return utils.FormatRelativeTime(value)
+	}
+	return c.value(ticket)
+}
+
+func (c ticketColumn) searchKind() query.FieldKind {
+	if c.kind == timeColumn || c.kind == dateColumn {
+		return query.TimeField
+	}
+	return query.TextField
+}
+
+var defaultColumns = []string{"key", "type", "summary", "status", "created"}
+
+func builtinColumn(id string, config utils.JiraBranchConfig) (ticketColumn, bool) {
+	switch id {
+	case "key":
+		return ticketColumn{title: "Key", kind: keyColumn, minWidth: 10,
+			value: func(t jira.JiraTicketsMsg) string { return t.Key }}, true
+	case "type":
+		return ticketColumn{title: "Type", field: "issuetype", minWidth: 10,
+			value: func(t jira.JiraTicketsMsg) string { return t.Type }}, true
+	case "summary":
+		return ticketColumn{title: "Summary", field: "summary", minWidth: 25, weight: 4,
+			value: func(t jira.JiraTicketsMsg) string { return t.Summary }}, true
+	case "status":
+		return ticketColumn{title: "Status", field: "status", minWidth: 14, weight: 1,
+			value: func(t jira.JiraTicketsMsg) string { return t.Status }}, true
+	case "created":
+		return ticketColumn{title: "Created", kind: timeColumn, field: "created", minWidth: 15,
+			value: func(t jira.JiraTicketsMsg) string { return t.Created }}, true
+	case "updated":
+		return ticketColumn{title: "Updated", kind: timeColumn, field: "updated", minWidth: 15,
+			value: func(t jira.JiraTicketsMsg) string { return t.Updated }}, true
+	case "priority":
+		return ticketColumn{title: "Priority", field: "priority", minWidth: 10,
+			value: func(t jira.JiraTicketsMsg) string { return t.Priority }}, true
+	case "assignee":
+		return ticketColumn{title: "Assignee", field: "assignee", minWidth: 14, weight: 1,
+			value: func(t jira.JiraTicketsMsg) string { return t.Assignee }}, true
+	case "reporter":
+		return ticketColumn{title: "Reporter", field: "reporter", minWidth: 14, weight: 1,
+			value: func(t jira.JiraTicketsMsg) string { return t.Reporter }}, true
+	case "labels":
+		return ticketColumn{title: "Labels", field: "labels", minWidth: 12, weight: 1,
+			value: func(t jira.JiraTicketsMsg) string { return strings.Join(t.Labels, ", ") }}, true
+	case "due":
+		return ticketColumn{title: "Due", kind: dateColumn, field: "duedate", minWidth: 10,
+			value: func(t jira.JiraTicketsMsg) string { return t.DueDate }}, true
+	case "parent":
+		return ticketColumn{title: "Parent", field: "parent", minWidth: 12, weight: 1,
+			value: func(t jira.JiraTicketsMsg) string {
+				return strings.TrimSpace(t.Parent + " " + t.ParentSummary)
+			}}, true
+	case "sprint":
+		field := config.GetSprintField()
+		return ticketColumn{title: "Sprint", field: field, minWidth: 12, weight: 1,
+			value: func(t jira.JiraTicketsMsg) string { return t.Fields[field] }}, true
+	case "points":
+		field := config.GetStoryPointsField()
+		return ticketColumn{title: "Points", kind: numberColumn, field: field, minWidth: 6,
+			value: func(t jira.JiraTicketsMsg) string { return t.Fields[field] }}, true
+	}
+
+	if strings.HasPrefix(id, "customfield_") {
+		return ticketColumn{title: id, field: id, minWidth: 12, weight: 1,
+			value: func(t jira.JiraTicketsMsg) string { return t.Fields[id] }}, true
+	}
+
+	return ticketColumn{}, false
+}
+
+// createColumns builds the ticket table columns from the config, falling back
+// to the default set.
+func createColumns(config utils.JiraBranchConfig) []ticketColumn {
+	columnConfigs := config.Columns
+	if len(columnConfigs) == 0 {
+		for _, id := range defaultColumns {
+			columnConfigs = append(columnConfigs, utils.ColumnConfig{Field: id})
+		}
+	}
+
+	columns := []ticketColumn{}
+	for _, columnConfig := range columnConfigs {
+		id := strings.ToLower(columnConfig.Field)
+		column, ok := builtinColumn(id, config)
+		if !ok {
+			utils.Log.Error().Str("column", columnConfig.Field).Msg("Unknown column in config")
+			continue
+		}
+		column.id = id
+		if columnConfig.Title != "" {
+			column.title = columnConfig.Title
+		}
+		if columnConfig.Width > 0 {
+			column.weight = columnConfig.Width
+		}
+		columns = append(columns, column)
+	}
+
+	if len(columns) == 0 {
+		return createColumns(utils.JiraBranchConfig{})
+	}
+	return columns
+}
+
+// ticketFields lists the Jira fields the columns need.
+func (m model) ticketFields() []string {
+	fields := []string{}
+	for _, column := range m.columns {
+		if column.field != "" {
+			fields = append(fields, column.field)
+		}
+	}
+	return fields
+}
+
+func (m model) findColumn(id string) (ticketColumn, bool) {
+	for _, column := range m.columns {
+		if column.id == id {
+			return column, true
+		}
+	}
+	return ticketColumn{}, false
+}
+
+// layoutColumnWidths gives every column its minimum width and splits what is
+// left by weight. Columns at the end are hidden when even the minimums don't
+// fit. padding is the space each column takes besides its content.
+func layoutColumnWidths(columns []ticketColumn, available int, padding int) []int {
+	widths := make([]int, len(columns))
+	used := 0
+	totalWeight := 0
+	for i, column := range columns {
+		if used+column.minWidth+padding > available && i > 0 {
+			break
+		}
+		widths[i] = column.minWidth
+		used += column.minWidth + padding
+		totalWeight += column.weight
+	}
+
+	spare := available - used
+	if spare <= 0 || totalWeight == 0 {
+		return widths
+	}
+
+	heaviest := -1
+	for i, column := range columns {
+		if widths[i] == 0 {
+			continue
+		}
+		extra := spare * column.weight / totalWeight
+		widths[i] += extra
+		used += extra
+		if heaviest == -1 || column.weight > columns[heaviest].weight {
+			heaviest = i
+		}
+	}
+	// rounding leftovers
+	widths[heaviest] += available - used
+
+	return widths
+}
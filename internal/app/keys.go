@@ -0,0 +1,364 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/joshwrn/jira-branch/internal/gui"
+	"github.com/joshwrn/jira-branch/internal/utils"
+)
+
+type keyMap struct {
+	Up           key.Binding
+	Down         key.Binding
+	PageUp       key.Binding
+	PageDown     key.Binding
+	HalfPageUp   key.Binding
+	HalfPageDown key.Binding
+	Top          key.Binding
+	Bottom       key.Binding
+
+	Select         key.Binding
+	ToggleSelect   key.Binding
+	Back           key.Binding
+	Search         key.Binding
+	JQL            key.Binding
+	Refresh        key.Binding
+	Sort           key.Binding
+	SortDirection  key.Binding
+	Preview        key.Binding
+	Tree           key.Binding
+	Expand         key.Binding
+	Collapse       key.Binding
+	Open           key.Binding
+	CopyKey        key.Binding
+	CopyLink       key.Binding
+	CopyMarkdown   key.Binding
+	CopyBranch     key.Binding
+	SwitchTab      key.Binding
+	Outbox         key.Binding
+	TimeTracking   key.Binding
+	PullRequest    key.Binding
+	DeleteBranch   key.Binding
+	SignOut        key.Binding
+	Help           key.Binding
+	Quit           key.Binding
+	ForceQuit      key.Binding
+	Confirm        key.Binding
+	HistoryUp      key.Binding
+	HistoryDown    key.Binding
+	FormOpen       key.Binding
+	FormCopyBranch key.Binding
+	Retry          key.Binding
+	RetryAll       key.Binding
+	Drop           key.Binding
+}
+
+func binding(desc string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(keyName(keys[0]), desc))
+}
+
+func defaultKeyMap() keyMap {
+	return keyMap{
+		Up:           binding("up", "k", "up"),
+		Down:         binding("down", "j", "down"),
+		PageUp:       binding("page up", "b", "pgup"),
+		PageDown:     binding("page down", "f", "pgdown"),
+		HalfPageUp:   binding("half page up", "u", "ctrl+u"),
+		HalfPageDown: binding("half page down", "d", "ctrl+d"),
+		Top:          binding("go to top", "g", "home"),
+		Bottom:       binding("go to bottom", "G", "end"),
+
+		Select:         binding("Select ticket", "enter"),
+		ToggleSelect:   binding("Multi-select", " "),
+		Back:           binding("Back", "esc"),
+		Search:         binding("Search", "/"),
+		JQL:            binding("JQL", ":"),
+		Refresh:        binding("Refresh", "r"),
+		Sort:           binding("Sort", "s"),
+		SortDirection:  binding("Flip sort", "i"),
+		Preview:        binding("Preview", "p"),
+		Tree:           binding("Tree", "t"),
+		Expand:         binding("Expand", "l", "right"),
+		Collapse:       binding("Collapse", "h", "left"),
+		Open:           binding("Open", "o"),
+		CopyKey:        binding("Copy key", "y"),
+		CopyLink:       binding("Copy link", "Y"),
+		CopyMarkdown:   binding("Copy Markdown link", "M"),
+		CopyBranch:     binding("Copy branch name", "B"),
+		SwitchTab:      binding("Switch tab", "tab"),
+		Outbox:         binding("Outbox", "O"),
+		TimeTracking:   binding("Time tracking", "T"),
+		PullRequest:    binding("Pull request", "P"),
+		DeleteBranch:   binding("Delete branch", "D"),
+		SignOut:        binding("Sign out", "S"),
+		Help:           binding("Help", "?"),
+		Quit:           binding("Quit", "q"),
+		ForceQuit:      binding("Quit", "ctrl+c"),
+		Confirm:        binding("Confirm", "enter"),
+		HistoryUp:      binding("Older query", "up"),
+		HistoryDown:    binding("Newer query", "down"),
+		FormOpen:       binding("Open in browser", "ctrl+o"),
+		FormCopyBranch: binding("Copy branch name", "ctrl+y"),
+		Retry:          binding("Retry", "r"),
+		RetryAll:       binding("Retry all", "R"),
+		Drop:           binding("Drop", "x"),
+	}
+}
+
+// keyName is how a key is shown in the help.
+func keyName(k string) string {
+	switch k {
+	case " ":
+		return "space"
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	}
+	return k
+}
+
+// configurable maps the names used in the config to the bindings.
+func (k *keyMap) configurable() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":             &k.Up,
+		"down":           &k.Down,
+		"pageUp":         &k.PageUp,
+		"pageDown":       &k.PageDown,
+		"halfPageUp":     &k.HalfPageUp,
+		"halfPageDown":   &k.HalfPageDown,
+		"top":            &k.Top,
+		"bottom":         &k.Bottom,
+		"select":         &k.Select,
+		"toggleSelect":   &k.ToggleSelect,
+		"back":           &k.Back,
+		"search":         &k.Search,
+		"jql":            &k.JQL,
+		"refresh":        &k.Refresh,
+		"sort":           &k.Sort,
+		"sortDirection":  &k.SortDirection,
+		"preview":        &k.Preview,
+		"tree":           &k.Tree,
+		"expand":         &k.Expand,
+		"collapse":       &k.Collapse,
+		"open":           &k.Open,
+		"copyKey":        &k.CopyKey,
+		"copyLink":       &k.CopyLink,
+		"copyMarkdown":   &k.CopyMarkdown,
+		"copyBranch":     &k.CopyBranch,
+		"switchTab":      &k.SwitchTab,
+		"outbox":         &k.Outbox,
+		"timeTracking":   &k.TimeTracking,
+		"pullRequest":    &k.PullRequest,
+		"deleteBranch":   &k.DeleteBranch,
+		"signOut":        &k.SignOut,
+		"help":           &k.Help,
+		"quit":           &k.Quit,
+		"confirm":        &k.Confirm,
+		"historyUp":      &k.HistoryUp,
+		"historyDown":    &k.HistoryDown,
+		"formOpen":       &k.FormOpen,
+		"formCopyBranch": &k.FormCopyBranch,
+		"retry":          &k.Retry,
+		"retryAll":       &k.RetryAll,
+		"drop":           &k.Drop,
+	}
+}
+
+type keyScope struct {
+	title string
+	// config names of the bindings
+	names []string
+}
+
+// keyScopes groups the bindings by where they are active. Keys only have to
+// be unique within a scope.
+var keyScopes = func() []keyScope {
+	navigation := []string{"up", "down", "pageUp", "pageDown", "halfPageUp", "halfPageDown", "top", "bottom"}
+	ticket := []string{"open", "copyKey", "copyLink", "copyMarkdown", "copyBranch"}
+
+	return []keyScope{
+		{title: "Tickets", names: slices.Concat(
+			[]string{"select", "toggleSelect", "back", "search", "jql", "refresh", "sort", "sortDirection", "preview", "tree", "expand", "collapse"},
+			ticket,
+			[]string{"switchTab", "outbox", "timeTracking", "pullRequest", "signOut", "help", "quit", "forceQuit"},
+			navigation,
+		)},
+		{title: "Recent", names: slices.Concat(
+			[]string{"select", "back", "refresh", "switchTab", "deleteBranch"},
+			ticket,
+			[]string{"help", "quit", "forceQuit"},
+			navigation,
+		)},
+		{title: "Search", names: []string{"confirm", "back", "historyUp", "historyDown", "forceQuit"}},
+		{title: "Branch form", names: []string{"formOpen", "formCopyBranch", "back", "forceQuit"}},
+		{title: "Outbox", names: slices.Concat(
+			[]string{"retry", "retryAll", "drop", "back", "forceQuit"},
+			navigation,
+		)},
+		{title: "Pull request", names: []string{"open", "copyLink", "back", "forceQuit"}},
+		{title: "Time tracking", names: slices.Concat(
+			[]string{"select", "drop", "refresh", "back", "forceQuit"},
+			navigation,
+		)},
+	}
+}()
+
+// bindings returns the bindings of a scope.
+func (k keyMap) bindings(scope keyScope) []key.Binding {
+	all := k.all()
+	bindings := []key.Binding{}
+	for _, name := range scope.names {
+		bindings = append(bindings, *all[name])
+	}
+	return bindings
+}
+
+type keyConflict struct {
+	key   string
+	names []string
+	scope string
+}
+
+func (c keyConflict) String() string {
+	return fmt.Sprintf("%q is bound to both %s and %s in %s", keyName(c.key), c.names[0], c.names[1], c.scope)
+}
+
+// conflicts lists keys that are bound to more than one action in a scope.
+func (k keyMap) conflicts() []keyConflict {
+	all := k.all()
+	conflicts := []keyConflict{}
+	for _, scope := range keyScopes {
+		owners := map[string]string{}
+		for _, name := range scope.names {
+			for _, keyString := range all[name].Keys() {
+				if owner, taken := owners[keyString]; taken {
+					conflicts = append(conflicts, keyConflict{
+						key:   keyString,
+						names: []string{owner, name},
+						scope: scope.title,
+					})
+				}
+				owners[keyString] = name
+			}
+		}
+	}
+	return conflicts
+}
+
+// all is every binding by name, including the ones that can't be configured.
+func (k *keyMap) all() map[string]*key.Binding {
+	all := k.configurable()
+	all["forceQuit"] = &k.ForceQuit
+	return all
+}
+
+// loadKeyMap applies the key overrides from the config. Overrides that clash
+// with another action go back to their default and are reported.
+func loadKeyMap(overrides map[string]utils.KeyList) (keyMap, []string) {
+	keys := defaultKeyMap()
+	defaults := defaultKeyMap()
+	warnings := []string{}
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bindings := keys.configurable()
+	overridden := map[string]bool{}
+	for _, name := range names {
+		b, ok := bindings[name]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("unknown key action %q", name))
+			continue
+		}
+		if len(overrides[name]) == 0 {
+			continue
+		}
+		*b = binding(b.Help().Desc, overrides[name]...)
+		overridden[name] = true
+	}
+
+	// every round puts at least one override back, so this ends
+	for {
+		conflicts := keys.conflicts()
+		if len(conflicts) == 0 {
+			break
+		}
+
+		conflict := conflicts[0]
+		reverted := false
+		for _, name := range conflict.names {
+			if overridden[name] {
+				*bindings[name] = *defaults.all()[name]
+				overridden[name] = false
+				reverted = true
+			}
+		}
+		if !reverted {
+			break
+		}
+		warnings = append(warnings, conflict.String())
+	}
+
+	return keys, warnings
+}
+
+// tableKeyMap lets the tables move with the configured navigation keys.
+func (k keyMap) tableKeyMap() table.KeyMap {
+	return table.KeyMap{
+		LineUp:       k.Up,
+		LineDown:     k.Down,
+		PageUp:       k.PageUp,
+		PageDown:     k.PageDown,
+		HalfPageUp:   k.HalfPageUp,
+		HalfPageDown: k.HalfPageDown,
+		GotoTop:      k.Top,
+		GotoBottom:   k.Bottom,
+	}
+}
+
+func helpItem(b key.Binding) gui.HelpItem {
+	return gui.HelpItem{Key: b.Help().Key, Desc: b.Help().Desc}
+}
+
+// helpAs shows a binding with a description that fits the current view.
+func helpAs(b key.Binding, desc string) gui.HelpItem {
+	return gui.HelpItem{Key: b.Help().Key, Desc: desc}
+}
+
+// helpItems builds a help bar from bindings.
+func helpItems(bindings ...key.Binding) []gui.HelpItem {
+	items := []gui.HelpItem{}
+	for _, b := range bindings {
+		if b.Enabled() {
+			items = append(items, helpItem(b))
+		}
+	}
+	return items
+}
+
+// navigationHelp is the short form of moving up and down.
+func (k keyMap) navigationHelp() gui.HelpItem {
+	return gui.HelpItem{Key: k.Down.Help().Key + "/" + k.Up.Help().Key, Desc: "↓/↑"}
+}
+
+func (k keyMap) quitHelp() gui.HelpItem {
+	return gui.HelpItem{Key: k.Quit.Help().Key + "/" + k.ForceQuit.Help().Key, Desc: "Quit"}
+}
+
+// allKeys lists every key of a binding for the help overlay.
+func allKeys(b key.Binding) string {
+	names := []string{}
+	for _, k := range b.Keys() {
+		names = append(names, keyName(k))
+	}
+	return strings.Join(names, "/")
+}
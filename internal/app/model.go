@@ -1,38 +1,149 @@
 package app
 
 import (
+	"time"
+
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/huh"
+	"github.com/joshwrn/jira-branch/internal/forge"
+	"github.com/joshwrn/jira-branch/internal/gui"
+	"github.com/joshwrn/jira-branch/internal/history"
 	"github.com/joshwrn/jira-branch/internal/jira"
+	"github.com/joshwrn/jira-branch/internal/outbox"
+	"github.com/joshwrn/jira-branch/internal/prefs"
+	"github.com/joshwrn/jira-branch/internal/query"
+	"github.com/joshwrn/jira-branch/internal/timetrack"
+	"github.com/joshwrn/jira-branch/internal/utils"
 )
 
 type model struct {
+	startedAt time.Time
+	config    utils.JiraBranchConfig
+	keys      keyMap
+	// problems with the config, shown once on startup
+	startupWarnings []string
+	showHelp        bool
+
 	isLoading  bool
 	isLoggedIn bool
 	err        error
 	width      int
 	height     int
 	view       string
+	// tab of the list view, "tickets" or "recent"
+	tab string
 
 	spinner spinner.Model
-	list    table.Model
+	list    gui.Table
 	form    *huh.Form
 
 	isSubmittingForm bool
+	// last line of progress while the form is submitted
+	submitProgress   string
+	submitProgressCh <-chan string
 
+	formTicket                 jira.JiraTicketsMsg
 	formBranchName             *string
 	formShouldMarkAsInProgress *bool
+	formShouldComment          *bool
+	formShouldPush             *bool
+	formInput                  *huh.Input
+	// the suggestion picked last, copied into the branch name when it changes
+	formSuggestion    *string
+	appliedSuggestion string
 
 	credentialInputs []textinput.Model
 	currentField     int
 	credentials      jira.Credentials
+	client           *jira.Client
 
+	columns    []ticketColumn
 	allTickets []jira.JiraTicketsMsg
 	tickets    []jira.JiraTicketsMsg
 
+	isRefreshing     bool
+	isStale          bool
+	isOffline        bool
+	lastUpdated      time.Time
+	clockID          int
+	queuedOperations int
+
+	outboxOperations []outbox.Operation
+	outboxTable      table.Model
+	outboxStatus     string
+	outboxErr        error
+
+	// time tracked on ticket branches that wasn't logged yet
+	timeTotals       []timetrack.Total
+	timeTable        table.Model
+	timeStatus       string
+	timeErr          error
+	worklogForm      *huh.Form
+	worklogTotal     timetrack.Total
+	worklogTimeSpent *string
+	worklogComment   *string
+
 	showSearch  bool
 	search      string
 	searchInput textinput.Model
+	searchQuery query.Query
+	searchErr   error
+	sort        prefs.Sort
+
+	// tree mode nests subtasks and stories under their parents
+	treeMode  bool
+	collapsed map[string]bool
+	// the rows of the list, in the same order as tickets
+	treeRows []treeRow
+
+	// jql replaces the default query when set, pendingJQL is the one being
+	// searched for right now
+	jql             string
+	pendingJQL      string
+	showJQLSearch   bool
+	jqlInput        textinput.Model
+	jqlErr          error
+	jqlHistory      []string
+	jqlHistoryIndex int
+
+	// selected tickets by key
+	selected        map[string]bool
+	batchForm       *huh.Form
+	batchAction     *string
+	batchTransition *string
+	batchResults    []batchResult
+	isRunningBatch  bool
+
+	// preview pane of the highlighted ticket, the details are cached by key
+	showPreview bool
+	previews    map[string]preview
+	previewKey  string
+	previewID   int
+
+	// pull request for the checked out branch
+	prBranch      string
+	prBase        string
+	prTicket      jira.IssueDetails
+	prForge       forge.Forge
+	prForm        *huh.Form
+	prTitle       *string
+	prBody        *string
+	prDraft       *bool
+	prLinkOnIssue *bool
+	prCreated     forge.CreatedPullRequest
+	prErr         error
+	isPreparingPR bool
+	isCreatingPR  bool
+
+	lastClick click
+
+	recentEntries []history.BranchEntry
+	recentTable   gui.Table
+	// branch waiting for the delete key to be pressed again
+	pendingDelete history.BranchEntry
+
+	toast   toastMsg
+	toastID int
 }
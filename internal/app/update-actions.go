@@ -0,0 +1,88 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshwrn/jira-branch/internal/browser"
+	"github.com/joshwrn/jira-branch/internal/clipboard"
+	"github.com/joshwrn/jira-branch/internal/git_utils"
+	"github.com/joshwrn/jira-branch/internal/jira"
+	"github.com/joshwrn/jira-branch/internal/utils"
+)
+
+const toastDuration = 3 * time.Second
+
+// toastMsg shows a short confirmation in the footer.
+type toastMsg struct {
+	text    string
+	isError bool
+}
+
+type toastExpiredMsg struct {
+	id int
+}
+
+func showToast(m model, msg toastMsg) (model, tea.Cmd) {
+	m.toast = msg
+	m.toastID++
+	id := m.toastID
+	return m, tea.Tick(toastDuration, func(time.Time) tea.Msg {
+		return toastExpiredMsg{id: id}
+	})
+}
+
+func openInBrowser(credentials jira.Credentials, key string) tea.Cmd {
+	return openURL(credentials.IssueURL(key), key)
+}
+
+// openURL opens url in the browser, or copies it when there is none. label
+// names the page in the toast.
+func openURL(url string, label string) tea.Cmd {
+	return func() tea.Msg {
+		err := browser.Open(url)
+		if err == nil {
+			return toastMsg{text: fmt.Sprintf("Opened %s in the browser", label)}
+		}
+
+		// usually a machine without a desktop, like over SSH
+		utils.Log.Info().Err(err).Msg("Failed to open browser")
+		if err := clipboard.Copy(url); err != nil {
+			return toastMsg{text: "Couldn't open a browser", isError: true}
+		}
+		return toastMsg{text: "Couldn't open a browser, copied the URL instead"}
+	}
+}
+
+func copyToClipboard(text string, label string) tea.Cmd {
+	return func() tea.Msg {
+		if err := clipboard.Copy(text); err != nil {
+			utils.Log.Error().Err(err).Msg("Failed to copy to clipboard")
+			return toastMsg{text: "Couldn't copy to the clipboard", isError: true}
+		}
+		return toastMsg{text: fmt.Sprintf("Copied %s", label)}
+	}
+}
+
+func markdownLink(credentials jira.Credentials, ticket jira.JiraTicketsMsg) string {
+	return fmt.Sprintf("[%s: %s](%s)", ticket.Key, ticket.Summary, credentials.IssueURL(ticket.Key))
+}
+
+// ticketAction runs the open and copy shortcuts of the list for a ticket.
+func ticketAction(m model, msg tea.KeyMsg, ticket jira.JiraTicketsMsg) (tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, m.keys.Open):
+		return openInBrowser(m.credentials, ticket.Key), true
+	case key.Matches(msg, m.keys.CopyKey):
+		return copyToClipboard(ticket.Key, ticket.Key), true
+	case key.Matches(msg, m.keys.CopyLink):
+		return copyToClipboard(m.credentials.IssueURL(ticket.Key), "link"), true
+	case key.Matches(msg, m.keys.CopyMarkdown):
+		return copyToClipboard(markdownLink(m.credentials, ticket), "Markdown link"), true
+	case key.Matches(msg, m.keys.CopyBranch):
+		return copyToClipboard(git_utils.FormatBranchName(ticket, m.branchNaming()), "branch name"), true
+	}
+	return nil, false
+}
@@ -0,0 +1,291 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/joshwrn/jira-branch/internal/clipboard"
+	"github.com/joshwrn/jira-branch/internal/git_utils"
+	"github.com/joshwrn/jira-branch/internal/jira"
+	"github.com/joshwrn/jira-branch/internal/outbox"
+)
+
+const (
+	batchTransition = "transition"
+	batchAssign     = "assign"
+	batchBranches   = "branches"
+	batchCopyKeys   = "copy"
+)
+
+type batchStatus int
+
+const (
+	batchPending batchStatus = iota
+	batchRunning
+	batchDone
+	batchQueued
+	batchFailed
+)
+
+type batchResult struct {
+	ticket jira.JiraTicketsMsg
+	status batchStatus
+	detail string
+	err    error
+}
+
+type batchResultMsg struct {
+	index  int
+	queued bool
+	detail string
+	err    error
+}
+
+func (m model) hasSelection() bool {
+	return len(m.selected) > 0
+}
+
+// selectedTickets returns the selected tickets in the order they were fetched,
+// including those the search hides.
+func (m model) selectedTickets() []jira.JiraTicketsMsg {
+	tickets := []jira.JiraTicketsMsg{}
+	for _, ticket := range m.allTickets {
+		if m.selected[ticket.Key] {
+			tickets = append(tickets, ticket)
+		}
+	}
+	return tickets
+}
+
+// hiddenSelection counts the selected tickets the search hides.
+func (m model) hiddenSelection() int {
+	hidden := len(m.selectedTickets())
+	for _, ticket := range m.tickets {
+		if m.selected[ticket.Key] {
+			hidden--
+		}
+	}
+	return hidden
+}
+
+// pruneSelection forgets selected tickets that are no longer in the list.
+func (m *model) pruneSelection() {
+	if !m.hasSelection() {
+		return
+	}
+	selected := map[string]bool{}
+	for _, ticket := range m.selectedTickets() {
+		selected[ticket.Key] = true
+	}
+	m.selected = selected
+}
+
+// rebuildRows redraws the table, e.g. when the selection markers change.
+func (m *model) rebuildRows() {
+	selectedKey := m.selectedTicketKey()
+	filterTickets(m)
+	m.selectTicket(selectedKey)
+}
+
+func toggleSelection(m model) (model, tea.Cmd) {
+	ticket, ok := m.actionableTicket()
+	if !ok {
+		return m, nil
+	}
+	key := ticket.Key
+
+	selected := map[string]bool{}
+	for k := range m.selected {
+		selected[k] = true
+	}
+	if selected[key] {
+		delete(selected, key)
+	} else {
+		selected[key] = true
+	}
+	m.selected = selected
+
+	m.rebuildRows()
+	m.list.MoveDown(1)
+	return m.schedulePreview()
+}
+
+func clearSelection(m model) model {
+	m.selected = nil
+	m.rebuildRows()
+	return m
+}
+
+func createBatchForm(m *model) *huh.Form {
+	action := batchTransition
+	transition := "In Progress"
+	m.batchAction = &action
+	m.batchTransition = &transition
+
+	count := len(m.selectedTickets())
+	description := ""
+	if hidden := m.hiddenSelection(); hidden > 0 {
+		description = fmt.Sprintf("%d of them are hidden by the search", hidden)
+	}
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(fmt.Sprintf("What should happen to the %d selected tickets?", count)).
+				Description(description).
+				Options(
+					huh.NewOption("Transition", batchTransition),
+					huh.NewOption("Assign to me", batchAssign),
+					huh.NewOption("Create branches (without checking out)", batchBranches),
+					huh.NewOption("Copy keys", batchCopyKeys),
+				).
+				Value(m.batchAction),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Transition to").
+				Value(m.batchTransition).
+				Validate(func(value string) error {
+					if strings.TrimSpace(value) == "" {
+						return fmt.Errorf("transition is required")
+					}
+					return nil
+				}),
+		).WithHideFunc(func() bool {
+			return *m.batchAction != batchTransition
+		}),
+	).WithTheme(customTheme())
+}
+
+func openBatch(m model) (model, tea.Cmd) {
+	if !m.hasSelection() {
+		return m, nil
+	}
+	m.view = "batch"
+	m.batchResults = nil
+	m.batchForm = createBatchForm(&m)
+	return m, m.batchForm.Init()
+}
+
+func startBatch(m model) (model, tea.Cmd) {
+	m.batchResults = []batchResult{}
+	for _, ticket := range m.selectedTickets() {
+		m.batchResults = append(m.batchResults, batchResult{ticket: ticket})
+	}
+
+	if *m.batchAction == batchCopyKeys {
+		keys := []string{}
+		for _, result := range m.batchResults {
+			keys = append(keys, result.ticket.Key)
+		}
+		err := clipboard.Copy(strings.Join(keys, ", "))
+		for i := range m.batchResults {
+			m.batchResults[i].status = batchDone
+			m.batchResults[i].detail = "copied"
+			if err != nil {
+				m.batchResults[i].status = batchFailed
+				m.batchResults[i].err = err
+			}
+		}
+		return m, nil
+	}
+
+	m.isRunningBatch = true
+	return m.runBatchStep(0)
+}
+
+// runBatchStep runs the action for one ticket at a time so the results show
+// up in order.
+func (m model) runBatchStep(index int) (model, tea.Cmd) {
+	if index >= len(m.batchResults) {
+		m.isRunningBatch = false
+		m.selected = nil
+		m.rebuildRows()
+		return m, nil
+	}
+
+	m.batchResults[index].status = batchRunning
+	ticket := m.batchResults[index].ticket
+	action := *m.batchAction
+	transition := strings.TrimSpace(*m.batchTransition)
+
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+		msg := batchResultMsg{index: index}
+		switch action {
+		case batchTransition:
+			msg.queued, msg.err = sendJiraOperation(m, outbox.Operation{
+				Kind:       outbox.KindTransition,
+				IssueKey:   ticket.Key,
+				Transition: transition,
+			})
+			msg.detail = fmt.Sprintf("moved to %s", transition)
+		case batchAssign:
+			msg.queued, msg.err = sendJiraOperation(m, outbox.Operation{
+				Kind:     outbox.KindAssign,
+				IssueKey: ticket.Key,
+			})
+			msg.detail = "assigned to you"
+		case batchBranches:
+			branchName := git_utils.FormatBranchName(ticket, m.branchNaming())
+			msg.err = git_utils.CreateBranch(branchName)
+			msg.detail = branchName
+			if msg.err == nil {
+				recordBranch(ticket, branchName, true)
+			}
+		}
+		return msg
+	})
+}
+
+func updateBatchResult(m model, msg batchResultMsg) (model, tea.Cmd) {
+	if msg.index >= len(m.batchResults) {
+		return m, nil
+	}
+
+	result := &m.batchResults[msg.index]
+	result.detail = msg.detail
+	switch {
+	case msg.err != nil:
+		result.status = batchFailed
+		result.err = msg.err
+	case msg.queued:
+		result.status = batchQueued
+	default:
+		result.status = batchDone
+	}
+
+	return m.runBatchStep(msg.index + 1)
+}
+
+func updateBatch(m model, msg tea.Msg) (model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case batchResultMsg:
+		return updateBatchResult(m, msg)
+	case tea.KeyMsg:
+		if key.Matches(msg, m.keys.Back) && !m.isRunningBatch {
+			m.view = "list"
+			if m.batchResults == nil {
+				return m, nil
+			}
+			m.batchResults = nil
+			// statuses and assignees probably changed
+			m, cmd := m.refreshTickets()
+			return m, tea.Batch(cmd, loadOutbox())
+		}
+	}
+
+	if m.batchResults != nil {
+		return m, nil
+	}
+
+	form, cmd := m.batchForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.batchForm = f
+		if m.batchForm.State == huh.StateCompleted {
+			return startBatch(m)
+		}
+	}
+	return m, cmd
+}
@@ -1,45 +1,291 @@
 package app
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
+	"github.com/joshwrn/jira-branch/internal/automation"
+	"github.com/joshwrn/jira-branch/internal/forge"
 	"github.com/joshwrn/jira-branch/internal/git_utils"
 	"github.com/joshwrn/jira-branch/internal/jira"
+	"github.com/joshwrn/jira-branch/internal/outbox"
+	"github.com/joshwrn/jira-branch/internal/utils"
 )
 
+// formAction is one of the actions in the footer of the form, run with its
+// key or by clicking it.
+type formAction struct {
+	binding key.Binding
+	run     func(m model) (model, tea.Cmd)
+}
+
+func formActions(m model) []formAction {
+	return []formAction{
+		{binding: m.keys.FormOpen, run: func(m model) (model, tea.Cmd) {
+			return m, openInBrowser(m.credentials, m.formTicket.Key)
+		}},
+		{binding: m.keys.FormCopyBranch, run: func(m model) (model, tea.Cmd) {
+			return m, copyToClipboard(*m.formBranchName, "branch name")
+		}},
+		{binding: m.keys.Back, run: func(m model) (model, tea.Cmd) {
+			m.view = "list"
+			return m, nil
+		}},
+	}
+}
+
+// remoteLinkOperation links the issue to its pushed branch on the forge, or
+// to the comparison with the default branch when remoteLink is "compare".
+func remoteLinkOperation(config utils.JiraBranchConfig, ticket jira.JiraTicketsMsg, branchName string) (outbox.Operation, error) {
+	remoteURL, err := git_utils.RemoteURL(config.GetRemote())
+	if err != nil {
+		return outbox.Operation{}, err
+	}
+	remote, err := forge.ParseRemote(remoteURL, config.Forges)
+	if err != nil {
+		return outbox.Operation{}, err
+	}
+
+	op := outbox.Operation{
+		Kind:      outbox.KindRemoteLink,
+		IssueKey:  ticket.Key,
+		LinkURL:   remote.BranchURL(branchName),
+		LinkTitle: fmt.Sprintf("Branch %s in %s", branchName, remote.Repo),
+	}
+	if config.RemoteLink == "compare" {
+		op.LinkURL = remote.CompareURL(git_utils.DefaultBranch(config.GetRemote()), branchName)
+		op.LinkTitle = fmt.Sprintf("Changes on %s in %s", branchName, remote.Repo)
+	}
+	return op, nil
+}
+
+type submitProgressMsg string
+
+// listenSubmitProgress waits for the next line of progress while the form
+// is submitted.
+func listenSubmitProgress(progress <-chan string) tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-progress
+		if !ok {
+			return nil
+		}
+		return submitProgressMsg(line)
+	}
+}
+
+// formStep is one thing submitting the form does. The same steps are listed
+// in the form before it is confirmed.
+type formStep struct {
+	description string
+	run         func(report func(line string)) error
+	// a failed Jira update doesn't stop the git steps after it
+	isJira bool
+}
+
+// formSteps plans what submitting the form does with what is picked in it
+// right now, including the automation rules.
+func formSteps(m model) []formStep {
+	ticket := m.formTicket
+	branchName := *m.formBranchName
+	remote := m.config.GetRemote()
+	repo := m.currentRepo()
+	created := !git_utils.BranchExists(branchName)
+	pushed := created && *m.formShouldPush
+	subject := automation.Subject{
+		IssueKey: ticket.Key,
+		Summary:  ticket.Summary,
+		Branch:   branchName,
+		Repo:     m.currentRepoName(),
+	}
+
+	steps := []formStep{}
+	addJiraStep := func(op outbox.Operation, failure string) {
+		steps = append(steps, formStep{
+			description: fmt.Sprintf("%s: %s", op.IssueKey, op.Description()),
+			run: func(func(string)) error {
+				_, err := sendJiraOperation(m, op)
+				if err != nil && failure != "" {
+					return fmt.Errorf("%s: %w", failure, err)
+				}
+				return err
+			},
+			isJira: true,
+		})
+	}
+	addRules := func(event automation.Event, failure string) {
+		for _, op := range automation.Operations(m.config.Automation, event, subject) {
+			addJiraStep(op, failure)
+		}
+	}
+
+	if *m.formShouldMarkAsInProgress {
+		addJiraStep(outbox.Operation{
+			Kind:       outbox.KindTransition,
+			IssueKey:   ticket.Key,
+			Transition: "In Progress",
+		}, fmt.Sprintf("couldn't move %s to In Progress", ticket.Key))
+	}
+
+	checkout := fmt.Sprintf("Check out %s", branchName)
+	if created {
+		checkout = fmt.Sprintf("Create and check out %s", branchName)
+	}
+	steps = append(steps, formStep{
+		description: checkout,
+		run: func(func(string)) error {
+			created, err := git_utils.CheckoutBranch(branchName)
+			if err != nil {
+				return err
+			}
+			recordBranch(ticket, branchName, created)
+			trackBranch(ticket.Key, ticket.Summary, repo, branchName)
+			return nil
+		},
+	})
+
+	if created && *m.formShouldComment {
+		addJiraStep(outbox.Operation{
+			Kind:     outbox.KindComment,
+			IssueKey: ticket.Key,
+			Comment:  branchComment(m.config, ticket, m.currentRepoName(), branchName),
+		}, fmt.Sprintf("created the branch, but couldn't comment on %s", ticket.Key))
+	}
+	if created {
+		addRules(automation.BranchCreated, fmt.Sprintf("created the branch, but couldn't update %s", ticket.Key))
+	}
+
+	if pushed {
+		steps = append(steps, formStep{
+			description: fmt.Sprintf("Push %s to %s", branchName, remote),
+			run: func(report func(string)) error {
+				if err := git_utils.PushBranch(remote, branchName, report); err != nil {
+					return fmt.Errorf("created the branch, but couldn't push it: %w", err)
+				}
+				return nil
+			},
+		})
+		addRules(automation.BranchPushed, fmt.Sprintf("pushed the branch, but couldn't update %s", ticket.Key))
+	}
+
+	if m.config.RemoteLink != "" && (pushed || git_utils.RemoteBranchExists(remote, branchName)) {
+		failure := fmt.Sprintf("checked out the branch, but couldn't link it on %s", ticket.Key)
+		op, err := remoteLinkOperation(m.config, ticket, branchName)
+		if err != nil {
+			steps = append(steps, formStep{
+				description: fmt.Sprintf("%s: Link the branch", ticket.Key),
+				run: func(func(string)) error {
+					return fmt.Errorf("%s: %w", failure, err)
+				},
+				isJira: true,
+			})
+		} else {
+			addJiraStep(op, failure)
+		}
+	}
+
+	return steps
+}
+
+// formJiraFailedMsg is sent when the branch is done but some of the Jira
+// updates failed, so the app stays open to show them.
+type formJiraFailedMsg struct {
+	errs []error
+}
+
+// submitForm runs the steps of the form, reporting what it is doing on
+// progress. Jira updates that fail are collected, so the branch is still
+// checked out and pushed.
+func submitForm(m model, progress chan<- string) tea.Msg {
+	report := func(line string) {
+		// drop lines the view can't keep up with, only the last one is shown
+		select {
+		case progress <- line:
+		default:
+		}
+	}
+
+	jiraErrs := []error{}
+	for _, step := range formSteps(m) {
+		report(step.description)
+		err := step.run(report)
+		switch {
+		case err == nil:
+		case step.isJira:
+			utils.Log.Error().Err(err).Str("step", step.description).Msg("Jira update failed, continuing")
+			jiraErrs = append(jiraErrs, err)
+		default:
+			return errMsg(errors.Join(append(jiraErrs, err)...))
+		}
+	}
+	if len(jiraErrs) > 0 {
+		return formJiraFailedMsg{errs: jiraErrs}
+	}
+	return tea.Quit()
+}
+
+// showFormJiraFailures goes back to the list once the branch is done, with
+// the Jira updates that failed in a toast.
+func showFormJiraFailures(m model, errs []error) (model, tea.Cmd) {
+	m.isSubmittingForm = false
+	m.view = "list"
+	failures := []string{}
+	for _, err := range errs {
+		failures = append(failures, err.Error())
+	}
+	text := strings.Join(failures, "; ")
+	text = strings.ToUpper(text[:1]) + text[1:]
+
+	m, toastCmd := showToast(m, toastMsg{text: text, isError: true})
+	m, refreshCmd := m.refreshTickets()
+	return m, tea.Batch(toastCmd, refreshCmd)
+}
+
 func updateForm(m model, msg tea.Msg) (model, tea.Cmd) {
 	if m.isSubmittingForm {
+		switch msg := msg.(type) {
+		case submitProgressMsg:
+			m.submitProgress = string(msg)
+			return m, listenSubmitProgress(m.submitProgressCh)
+		case formJiraFailedMsg:
+			return showFormJiraFailures(m, msg.errs)
+		}
 		return m, nil
 	}
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "esc":
-			m.view = "list"
-			return m, nil
+		for _, action := range formActions(m) {
+			if key.Matches(msg, action.binding) {
+				return action.run(m)
+			}
 		}
+	case tea.MouseMsg:
+		return updateFormMouse(m, msg)
 	}
 	form, formCmd := m.form.Update(msg)
 	if f, ok := form.(*huh.Form); ok {
 		m.form = f
+		if m.formSuggestion != nil && *m.formSuggestion != m.appliedSuggestion {
+			m.appliedSuggestion = *m.formSuggestion
+			*m.formBranchName = m.appliedSuggestion
+			m.formInput.Value(m.formBranchName)
+		}
 		if m.form.State != huh.StateCompleted {
 			return m, formCmd
 		}
 		m.isSubmittingForm = true
+		m.submitProgress = ""
+		progress := make(chan string, 16)
+		m.submitProgressCh = progress
 		return m, tea.Batch(
 			m.spinner.Tick,
+			listenSubmitProgress(progress),
 			func() tea.Msg {
-				if *m.formShouldMarkAsInProgress {
-					err := jira.MarkAsInProgress(
-						m.credentials,
-						m.list.SelectedRow()[0],
-					)
-					if err != nil {
-						return errMsg(err)
-					}
-				}
-				checkCmd := git_utils.CheckoutBranch(*m.formBranchName)
-				return checkCmd()
+				defer close(progress)
+				return submitForm(m, progress)
 			},
 		)
 	}
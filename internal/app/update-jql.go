@@ -0,0 +1,148 @@
+package app
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshwrn/jira-branch/internal/cache"
+	"github.com/joshwrn/jira-branch/internal/history"
+	"github.com/joshwrn/jira-branch/internal/jira"
+	"github.com/joshwrn/jira-branch/internal/utils"
+)
+
+type jqlHistoryMsg []string
+
+func loadJQLHistory() tea.Cmd {
+	return func() tea.Msg {
+		queries, err := history.LoadJQL()
+		if err != nil {
+			utils.Log.Error().Err(err).Msg("Failed to read JQL history")
+		}
+		return jqlHistoryMsg(queries)
+	}
+}
+
+func openJQLSearch(m model) (model, tea.Cmd) {
+	m.jqlInput = createJQLInput(m.width)
+	m.jqlInput.SetValue(m.jql)
+	m.jqlInput.CursorEnd()
+	m.jqlInput.Focus()
+	m.jqlErr = nil
+	m.jqlHistoryIndex = -1
+	m.showJQLSearch = true
+	m.updateTableSize()
+	return m, loadJQLHistory()
+}
+
+func closeJQLSearch(m model) model {
+	m.showJQLSearch = false
+	m.pendingJQL = ""
+	m.jqlErr = nil
+	m.updateTableSize()
+	return m
+}
+
+func submitJQLSearch(m model) (model, tea.Cmd) {
+	jql := jira.SearchToJQL(m.jqlInput.Value())
+
+	// an empty search goes back to the default query
+	if jql == "" {
+		m = closeJQLSearch(m)
+		if m.jql == "" {
+			return m, nil
+		}
+		m.jql = ""
+		m.loadSort()
+		if entry, ok := cache.Load(m.ticketsCacheKey()); ok {
+			m.allTickets = entry.Tickets
+			m.lastUpdated = entry.FetchedAt
+			m.isStale = true
+			filterTickets(&m)
+			m.list.GotoTop()
+		}
+		m.isRefreshing = false
+		clock := m.restartClock()
+		m, cmd := m.refreshTickets()
+		return m, tea.Batch(cmd, clock)
+	}
+
+	m.pendingJQL = jql
+	m.jqlErr = nil
+	m.updateTableSize()
+	return m, tea.Batch(
+		fetchTickets(m.client, cache.Key(m.credentials, jql), jql, m.ticketFields()),
+		m.spinner.Tick,
+	)
+}
+
+// updateJQLResults replaces the list with the results of a JQL search, or
+// keeps the search open with Jira's complaint about the query.
+func updateJQLResults(m model, msg ticketsMsg) (model, tea.Cmd) {
+	m.pendingJQL = ""
+	if msg.err != nil {
+		m.jqlErr = msg.err
+		m.updateTableSize()
+		return m, nil
+	}
+
+	if queries, err := history.AddJQL(msg.jql); err != nil {
+		utils.Log.Error().Err(err).Msg("Failed to save JQL history")
+	} else {
+		m.jqlHistory = queries
+	}
+
+	m.jql = msg.jql
+	m.loadSort()
+	m = closeJQLSearch(m)
+	m, cmd := updateTickets(m, msg)
+	m.list.GotoTop()
+	m, previewCmd := m.schedulePreview()
+	return m, tea.Batch(cmd, previewCmd)
+}
+
+// browseJQLHistory steps through the recent queries, -1 being the query that
+// was typed before browsing.
+func browseJQLHistory(m model, step int) model {
+	index := max(-1, min(m.jqlHistoryIndex+step, len(m.jqlHistory)-1))
+	if index == m.jqlHistoryIndex {
+		return m
+	}
+	m.jqlHistoryIndex = index
+	if index == -1 {
+		m.jqlInput.SetValue(m.jql)
+	} else {
+		m.jqlInput.SetValue(m.jqlHistory[index])
+	}
+	m.jqlInput.CursorEnd()
+	m.jqlErr = nil
+	m.updateTableSize()
+	return m
+}
+
+func updateJQLSearch(m model, msg tea.Msg) (model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Confirm):
+			if m.pendingJQL != "" {
+				return m, nil
+			}
+			return submitJQLSearch(m)
+		case key.Matches(msg, m.keys.Back):
+			return closeJQLSearch(m), nil
+		case key.Matches(msg, m.keys.HistoryUp):
+			return browseJQLHistory(m, 1), nil
+		case key.Matches(msg, m.keys.HistoryDown):
+			return browseJQLHistory(m, -1), nil
+		}
+	}
+
+	previousValue := m.jqlInput.Value()
+	updatedInput, cmd := m.jqlInput.Update(msg)
+	m.jqlInput = updatedInput
+	if m.jqlInput.Value() != previousValue && m.jqlErr != nil {
+		// the error points into the old text
+		m.jqlErr = nil
+		m.updateTableSize()
+	}
+	return m, cmd
+}
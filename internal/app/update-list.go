@@ -1,82 +1,313 @@
 package app
 
 import (
-	"github.com/charmbracelet/bubbles/table"
+	"errors"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshwrn/jira-branch/internal/cache"
 	"github.com/joshwrn/jira-branch/internal/git_utils"
+	"github.com/joshwrn/jira-branch/internal/gui"
 	"github.com/joshwrn/jira-branch/internal/jira"
+	"github.com/joshwrn/jira-branch/internal/utils"
 	"github.com/zalando/go-keyring"
 )
 
 func (m *model) updateTableSize() {
 	if m.width > 0 && m.height > 0 {
-		keyWidth := 10
-		typeWidth := 10
-		statusWidth := 25
-		createdWidth := 15
-		summaryWidth := max(25, m.width-keyWidth-typeWidth-statusWidth-createdWidth-12)
-
-		columns := []table.Column{
-			{Title: "Key", Width: keyWidth},
-			{Title: "Type", Width: typeWidth},
-			{Title: "Summary", Width: summaryWidth},
-			{Title: "Status", Width: statusWidth},
-			{Title: "Created", Width: createdWidth},
+		padding := m.list.Styles.Cell.GetHorizontalPadding()
+		available := m.width - 2 - m.previewWidth()
+		columns := []gui.Column{}
+		if m.hasSelection() {
+			columns = append(columns, gui.Column{Width: 1})
+			available -= 1 + padding
+		}
+		widths := layoutColumnWidths(m.treeLayoutColumns(), available, padding)
+
+		for i, column := range m.columns {
+			columns = append(columns, gui.Column{
+				Title: m.sortTitle(column.id, column.title),
+				Width: widths[i],
+			})
 		}
 		m.list.SetColumns(columns)
-		m.list.SetWidth(m.width - 2)
-		height := m.height - 3
+		m.list.SetWidth(m.width - 2 - m.previewWidth())
+		// borders, the tabs and the help bar
+		height := m.height - 4
 		if m.showSearch {
 			height = height - 1
+			if m.searchErr != nil {
+				height = height - 1
+			}
+		}
+		if m.showJQLSearch {
+			height = height - 1 - len(jqlErrorLines(*m))
 		}
 		m.list.SetHeight(height)
 	}
 }
 
 type ticketsMsg struct {
+	// the query the tickets were fetched with
+	jql                       string
 	tickets                   []jira.JiraTicketsMsg
 	shouldOverwriteAllTickets bool
 	err                       error
 }
 
+type autoRefreshMsg struct{}
+
+type clockMsg struct {
+	id int
+}
+
+func (m model) ticketsQuery() string {
+	if m.jql != "" {
+		return m.jql
+	}
+	return jira.BuildJQL(m.config)
+}
+
+func (m model) ticketsCacheKey() string {
+	return cache.Key(m.credentials, m.ticketsQuery())
+}
+
+// refreshTickets fetches the tickets in the background unless a fetch is
+// already in flight.
+func (m model) refreshTickets() (model, tea.Cmd) {
+	if m.isRefreshing || m.client == nil {
+		return m, nil
+	}
+	m.isRefreshing = true
+	return m, tea.Batch(
+		fetchTickets(m.client, m.ticketsCacheKey(), m.ticketsQuery(), m.ticketFields()),
+		m.spinner.Tick,
+	)
+}
+
+func scheduleAutoRefresh(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return autoRefreshMsg{}
+	})
+}
+
+// tickClock re-renders so relative times stay current, every second while
+// the tickets were updated less than a minute ago and on the minute after.
+func tickClock(m model) tea.Cmd {
+	id := m.clockID
+	tick := func(time.Time) tea.Msg {
+		return clockMsg{id: id}
+	}
+	if !m.lastUpdated.IsZero() && time.Since(m.lastUpdated) < time.Minute {
+		return tea.Tick(time.Second, tick)
+	}
+	return tea.Every(time.Minute, tick)
+}
+
+// restartClock starts ticking again after the tickets were updated, the
+// ticks that are still on their way are ignored.
+func (m *model) restartClock() tea.Cmd {
+	m.clockID++
+	return tickClock(*m)
+}
+
+func fetchTickets(client *jira.Client, cacheKey string, jql string, fields []string) tea.Cmd {
+	return func() tea.Msg {
+		start := time.Now()
+		tickets, err := client.GetJiraTickets(jql, fields)
+
+		// a rejected search is the first sign of missing or expired credentials
+		var apiErr *jira.APIError
+		if errors.As(err, &apiErr) && apiErr.Kind() == jira.ErrorKindUnauthorized {
+			return credentialsNeededMsg{}
+		}
+
+		utils.Log.Info().
+			Int("count", len(tickets)).
+			Dur("duration", time.Since(start)).
+			Msg("Fetched Jira tickets")
+
+		if err == nil {
+			if err := cache.Save(cacheKey, tickets); err != nil {
+				utils.Log.Error().Err(err).Msg("Failed to save ticket cache")
+			}
+		}
+		return ticketsMsg{
+			jql:                       jql,
+			tickets:                   tickets,
+			err:                       err,
+			shouldOverwriteAllTickets: true,
+		}
+	}
+}
+
+func (m model) selectedTicket() (jira.JiraTicketsMsg, bool) {
+	cursor := m.list.Cursor()
+	if cursor < 0 || cursor >= len(m.tickets) {
+		return jira.JiraTicketsMsg{}, false
+	}
+	return m.tickets[cursor], true
+}
+
+// actionableTicket is the highlighted ticket, unless it's a parent that is
+// only shown to group its children in tree mode.
+func (m model) actionableTicket() (jira.JiraTicketsMsg, bool) {
+	if row, ok := m.selectedTreeRow(); ok && row.isPlaceholder {
+		return jira.JiraTicketsMsg{}, false
+	}
+	return m.selectedTicket()
+}
+
+func (m model) selectedTicketKey() string {
+	ticket, _ := m.selectedTicket()
+	return ticket.Key
+}
+
+// selectTicket moves the cursor back to a ticket after the rows were rebuilt.
+func (m *model) selectTicket(key string) {
+	for i, ticket := range m.tickets {
+		if ticket.Key == key {
+			m.list.SetCursor(i)
+			return
+		}
+	}
+}
+
+func updateTickets(m model, msg ticketsMsg) (model, tea.Cmd) {
+	if m.pendingJQL != "" && msg.jql == m.pendingJQL {
+		return updateJQLResults(m, msg)
+	}
+
+	m.isRefreshing = false
+	// a refresh of the query that was active before a JQL search
+	if msg.jql != m.ticketsQuery() {
+		return m, nil
+	}
+	if msg.err != nil {
+		// keep showing the cached tickets when Jira can't be reached
+		if jira.IsNetworkError(msg.err) && len(m.allTickets) > 0 {
+			utils.Log.Info().Err(msg.err).Msg("Jira unreachable, working offline")
+			m.isOffline = true
+			m.isLoading = false
+			return m, nil
+		}
+		m.err = msg.err
+		m.isLoading = false
+		return m, nil
+	}
+	if msg.shouldOverwriteAllTickets {
+		m.allTickets = msg.tickets
+		m.lastUpdated = time.Now()
+		m.isStale = false
+		m.isOffline = false
+		m.pruneSelection()
+	}
+	m.tickets = msg.tickets
+
+	selectedKey := m.selectedTicketKey()
+	filterTickets(&m)
+	m.selectTicket(selectedKey)
+
+	m.err = nil
+	m.isLoading = false
+	m.isLoggedIn = true
+	m.logStartup("jira")
+
+	var previewCmd tea.Cmd
+	if msg.shouldOverwriteAllTickets {
+		m, previewCmd = m.clearPreviews()
+	} else {
+		m, previewCmd = m.schedulePreview()
+	}
+	return m, tea.Batch(replayOutbox(m.client, false), previewCmd, m.restartClock())
+}
+
+func showCredentials(m model) (model, tea.Cmd) {
+	m.view = "credentials"
+	m.pendingJQL = ""
+	m.isLoggedIn = false
+	m.isLoading = false
+	m.credentialInputs = CreateCredentialInputs(m.width)
+	m.currentField = 0
+	return m, textinput.Blink
+}
+
+// selectTickets opens the batch actions when tickets are selected, or the
+// branch form of the highlighted ticket.
+func selectTickets(m model) (model, tea.Cmd) {
+	if m.hasSelection() {
+		return openBatch(m)
+	}
+	if m.view == "list" {
+		if selectedTicket, ok := m.actionableTicket(); ok {
+			selected_branch := git_utils.FormatBranchName(selectedTicket, m.branchNaming())
+			m.view = "form"
+			m.formTicket = selectedTicket
+
+			m.form = createForm(&m, selected_branch)
+			return m, m.form.Init()
+		}
+	}
+	return m, nil
+}
+
 func updateList(m model, msg tea.Msg) (model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		return updateListMouse(m, msg)
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "r":
-			m.isLoading = true
-			tickets, err := jira.GetJiraTickets(m.credentials)
-			return m, tea.Batch(
-				func() tea.Msg {
-					return ticketsMsg{
-						tickets:                   tickets,
-						err:                       err,
-						shouldOverwriteAllTickets: true,
-					}
-				},
-				m.spinner.Tick,
-			)
-		case "S":
-			m.view = "credentials"
-			m.credentialInputs = CreateCredentialInputs(m.width)
-			m.currentField = 0
-			m.isLoggedIn = false
+		if ticket, ok := m.actionableTicket(); ok {
+			if cmd, ok := ticketAction(m, msg, ticket); ok {
+				return m, cmd
+			}
+		}
+
+		switch {
+		case key.Matches(msg, m.keys.Help):
+			m.showHelp = true
+			return m, nil
+		case key.Matches(msg, m.keys.Refresh):
+			return m.refreshTickets()
+		case key.Matches(msg, m.keys.SignOut):
+			m.err = nil
 			keyring.Delete("jira-cli", "credentials")
-			return m, textinput.Blink
-		case "enter":
-			if m.view == "list" && len(m.tickets) > 0 {
-				selectedRow := m.list.Cursor()
-				if selectedRow < len(m.tickets) {
-					selectedTicket := m.tickets[selectedRow]
-					selected_branch := git_utils.FormatBranchName(selectedTicket)
-					m.view = "form"
-
-					m.form = createForm(&m, selected_branch)
-					return m, m.form.Init()
-				}
+			return showCredentials(m)
+		case key.Matches(msg, m.keys.ToggleSelect):
+			return toggleSelection(m)
+		case key.Matches(msg, m.keys.Back):
+			if m.hasSelection() {
+				return clearSelection(m), nil
 			}
-		case "/":
+		case key.Matches(msg, m.keys.Select):
+			return selectTickets(m)
+		case key.Matches(msg, m.keys.Outbox):
+			m.view = "outbox"
+			m.outboxStatus = ""
+			m.outboxErr = nil
+			return m, loadOutbox()
+		case key.Matches(msg, m.keys.TimeTracking):
+			return openTime(m)
+		case key.Matches(msg, m.keys.PullRequest):
+			return openPullRequest(m)
+		case key.Matches(msg, m.keys.SwitchTab):
+			return switchTab(m)
+		case key.Matches(msg, m.keys.Sort):
+			return cycleSort(m)
+		case key.Matches(msg, m.keys.SortDirection):
+			return toggleSortDirection(m)
+		case key.Matches(msg, m.keys.Preview):
+			return togglePreview(m)
+		case key.Matches(msg, m.keys.Tree):
+			return toggleTree(m)
+		case key.Matches(msg, m.keys.Expand):
+			return expandRow(m)
+		case key.Matches(msg, m.keys.Collapse):
+			return collapseRow(m)
+		case key.Matches(msg, m.keys.JQL):
+			return openJQLSearch(m)
+		case key.Matches(msg, m.keys.Search):
 			m.searchInput = createSearchInput(m.width)
 			m.searchInput.SetValue(m.search)
 			m.searchInput.Focus()
@@ -84,31 +315,11 @@ func updateList(m model, msg tea.Msg) (model, tea.Cmd) {
 			m.updateTableSize()
 			return m, nil
 		}
-	case credentialsNeededMsg:
-		m.view = "credentials"
-		m.credentialInputs = CreateCredentialInputs(m.width)
-		m.currentField = 0
-		return m, textinput.Blink
-	case ticketsMsg:
-		if msg.err != nil {
-			m.err = msg.err
-			m.isLoading = false
-			return m, nil
-		}
-		if msg.shouldOverwriteAllTickets {
-			m.allTickets = msg.tickets
-		}
-		m.tickets = msg.tickets
-
-		filterTickets(&m)
-
-		m.isLoading = false
-		m.isLoggedIn = true
-		return m, nil
 	}
 
 	updatedTable, cmd := m.list.Update(msg)
 	m.list = updatedTable
-	return m, cmd
 
+	m, previewCmd := m.schedulePreview()
+	return m, tea.Batch(cmd, previewCmd)
 }
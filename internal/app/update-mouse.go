@@ -0,0 +1,124 @@
+package app
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshwrn/jira-branch/internal/gui"
+)
+
+// doubleClickTime is how close two clicks on the same row have to be to
+// count as a double click.
+const doubleClickTime = 400 * time.Millisecond
+
+// The tables of the list view start below the tabs and the top border.
+const (
+	tableTop  = 2
+	tableLeft = 1
+)
+
+type click struct {
+	at  time.Time
+	tab string
+	row int
+}
+
+func isLeftClick(msg tea.MouseMsg) bool {
+	return msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft
+}
+
+// registerClick remembers a click on a row and reports whether it completes
+// a double click.
+func (m *model) registerClick(row int) bool {
+	now := time.Now()
+	last := m.lastClick
+	if last.tab == m.tab && last.row == row && now.Sub(last.at) < doubleClickTime {
+		m.lastClick = click{}
+		return true
+	}
+	m.lastClick = click{at: now, tab: m.tab, row: row}
+	return false
+}
+
+func clickTab(m model, x int) (model, tea.Cmd) {
+	if tab, ok := tabAt(x); ok && tab != m.tab {
+		return switchTab(m)
+	}
+	return m, nil
+}
+
+func updateListMouse(m model, msg tea.MouseMsg) (model, tea.Cmd) {
+	if !isLeftClick(msg) {
+		m.list, _ = m.list.Update(msg)
+		return m.schedulePreview()
+	}
+	if msg.Y == 0 {
+		return clickTab(m, msg.X)
+	}
+
+	x, y := msg.X-tableLeft, msg.Y-tableTop
+	if x < 0 || x >= m.list.Width() {
+		return m, nil
+	}
+
+	if m.list.IsHeader(y) {
+		index, ok := m.list.ColumnAt(x)
+		// the marker column in front while tickets are selected
+		if m.hasSelection() {
+			index--
+		}
+		if !ok || index < 0 || index >= len(m.columns) {
+			return m, nil
+		}
+		return sortByColumn(m, m.columns[index])
+	}
+
+	row, ok := m.list.RowAt(y)
+	if !ok {
+		return m, nil
+	}
+	m.list.SetCursor(row)
+	if m.registerClick(row) {
+		return selectTickets(m)
+	}
+	return m.schedulePreview()
+}
+
+func updateRecentMouse(m model, msg tea.MouseMsg) (model, tea.Cmd) {
+	if !isLeftClick(msg) {
+		m.recentTable, _ = m.recentTable.Update(msg)
+		return m, nil
+	}
+	if msg.Y == 0 {
+		return clickTab(m, msg.X)
+	}
+
+	row, ok := m.recentTable.RowAt(msg.Y - tableTop)
+	if !ok {
+		return m, nil
+	}
+	m.recentTable.SetCursor(row)
+	if entry, ok := m.selectedRecent(); ok && m.registerClick(row) {
+		return m, checkoutRecent(entry)
+	}
+	return m, nil
+}
+
+// updateFormMouse makes the actions in the footer of the form clickable.
+func updateFormMouse(m model, msg tea.MouseMsg) (model, tea.Cmd) {
+	if !isLeftClick(msg) {
+		return m, nil
+	}
+
+	footerTop := formPadding + lipgloss.Height(m.form.View()) + 1
+	if msg.Y != footerTop {
+		return m, nil
+	}
+
+	index, ok := gui.HelpItemAt(formFooterItems(m), msg.X-formPadding)
+	if !ok {
+		return m, nil
+	}
+	return formActions(m)[index].run(m)
+}
@@ -0,0 +1,173 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshwrn/jira-branch/internal/gui"
+	"github.com/joshwrn/jira-branch/internal/jira"
+	"github.com/joshwrn/jira-branch/internal/outbox"
+	"github.com/joshwrn/jira-branch/internal/utils"
+)
+
+type outboxMsg struct {
+	operations []outbox.Operation
+	status     string
+	err        error
+}
+
+func loadOutbox() tea.Cmd {
+	return func() tea.Msg {
+		operations, err := outbox.Load()
+		if err != nil {
+			utils.Log.Error().Err(err).Msg("Failed to read outbox")
+		}
+		return outboxMsg{operations: operations}
+	}
+}
+
+// replayOutbox sends the queued operations. Held ones are only included when
+// asked for from the outbox.
+func replayOutbox(client *jira.Client, includeHeld bool) tea.Cmd {
+	return func() tea.Msg {
+		result, err := outbox.Replay(client, includeHeld)
+		if err != nil {
+			utils.Log.Error().Err(err).Msg("Failed to replay outbox")
+		}
+		if result.Sent > 0 || result.Failed > 0 {
+			utils.Log.Info().
+				Int("sent", result.Sent).
+				Int("failed", result.Failed).
+				Msg("Replayed queued Jira operations")
+		}
+
+		msg := loadOutbox()().(outboxMsg)
+		msg.err = err
+		if result.Sent > 0 {
+			msg.status = fmt.Sprintf("Sent %d queued operations", result.Sent)
+		}
+		return msg
+	}
+}
+
+func retryOperation(client *jira.Client, id string) tea.Cmd {
+	return func() tea.Msg {
+		err := outbox.Retry(client, id)
+		msg := loadOutbox()().(outboxMsg)
+		msg.err = err
+		if err == nil {
+			msg.status = "Sent"
+		}
+		return msg
+	}
+}
+
+func dropOperation(id string) tea.Cmd {
+	return func() tea.Msg {
+		err := outbox.Drop(id)
+		msg := loadOutbox()().(outboxMsg)
+		msg.err = err
+		if err == nil {
+			msg.status = "Dropped"
+		}
+		return msg
+	}
+}
+
+// sendJiraOperation runs op against Jira, queueing it when Jira can't be
+// reached so the rest of the work can carry on. It reports whether op was
+// queued.
+func sendJiraOperation(m model, op outbox.Operation) (bool, error) {
+	if m.isOffline {
+		return true, outbox.Enqueue(op)
+	}
+	return outbox.Send(m.client, op)
+}
+
+func (m *model) updateOutboxTable() {
+	rows := []table.Row{}
+	for _, op := range m.outboxOperations {
+		attempts := fmt.Sprintf("%d", op.Attempts)
+		if op.Held {
+			attempts += ", held"
+		}
+		rows = append(rows, table.Row{
+			op.IssueKey,
+			op.Description(),
+			utils.FormatTimeAgo(op.CreatedAt),
+			attempts,
+			op.LastError,
+		})
+	}
+
+	if m.outboxTable.Columns() == nil {
+		m.outboxTable = table.New(table.WithFocused(true))
+		m.outboxTable.KeyMap = m.keys.tableKeyMap()
+		s := table.DefaultStyles()
+		s.Header = s.Header.
+			BorderStyle(lipgloss.NormalBorder()).
+			BorderForeground(gui.CurrentTheme.Border).
+			BorderBottom(true).
+			Bold(false)
+		s.Selected = gui.SelectedStyle().Bold(false)
+		m.outboxTable.SetStyles(s)
+	}
+
+	keyWidth := 12
+	operationWidth := 30
+	queuedWidth := 15
+	attemptsWidth := 10
+	errorWidth := max(20, m.width-keyWidth-operationWidth-queuedWidth-attemptsWidth-12)
+
+	m.outboxTable.SetColumns([]table.Column{
+		{Title: "Issue", Width: keyWidth},
+		{Title: "Operation", Width: operationWidth},
+		{Title: "Queued", Width: queuedWidth},
+		{Title: "Tries", Width: attemptsWidth},
+		{Title: "Last error", Width: errorWidth},
+	})
+	m.outboxTable.SetRows(rows)
+	m.outboxTable.SetWidth(m.width - 2)
+	m.outboxTable.SetHeight(m.height - 5)
+}
+
+func updateOutbox(m model, msg tea.Msg) (model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Back):
+			m.view = "list"
+			m.outboxStatus = ""
+			return m, nil
+		case key.Matches(msg, m.keys.Retry):
+			if op, ok := m.selectedOperation(); ok {
+				m.outboxStatus = fmt.Sprintf("Retrying %s...", op.IssueKey)
+				return m, retryOperation(m.client, op.ID)
+			}
+		case key.Matches(msg, m.keys.RetryAll):
+			if len(m.outboxOperations) > 0 {
+				m.outboxStatus = "Retrying all..."
+				return m, replayOutbox(m.client, true)
+			}
+		case key.Matches(msg, m.keys.Drop):
+			if op, ok := m.selectedOperation(); ok {
+				return m, dropOperation(op.ID)
+			}
+		}
+	}
+
+	updatedTable, cmd := m.outboxTable.Update(msg)
+	m.outboxTable = updatedTable
+	return m, cmd
+}
+
+func (m model) selectedOperation() (outbox.Operation, bool) {
+	cursor := m.outboxTable.Cursor()
+	if cursor < 0 || cursor >= len(m.outboxOperations) {
+		return outbox.Operation{}, false
+	}
+	return m.outboxOperations[cursor], true
+}
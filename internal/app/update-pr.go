@@ -0,0 +1,317 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/joshwrn/jira-branch/internal/automation"
+	"github.com/joshwrn/jira-branch/internal/forge"
+	"github.com/joshwrn/jira-branch/internal/git_utils"
+	"github.com/joshwrn/jira-branch/internal/gui"
+	"github.com/joshwrn/jira-branch/internal/history"
+	"github.com/joshwrn/jira-branch/internal/jira"
+	"github.com/joshwrn/jira-branch/internal/outbox"
+	"github.com/joshwrn/jira-branch/internal/utils"
+	"github.com/mattn/go-runewidth"
+)
+
+// descriptionExcerptLength is how much of the ticket's description goes into
+// the body of a pull request.
+const descriptionExcerptLength = 600
+
+var issueKeyRegex = regexp.MustCompile(`(?i)\b([a-z][a-z0-9]+-\d+)\b`)
+
+type prPreparedMsg struct {
+	details jira.IssueDetails
+	forge   forge.Forge
+	err     error
+}
+
+type prCreatedMsg struct {
+	created forge.CreatedPullRequest
+	// updating the issue is a bonus, so it doesn't fail the whole thing
+	jiraErr     error
+	jiraUpdates int
+	queued      bool
+	err         error
+}
+
+// branchIssueKey finds the ticket of a branch, first in the branch history
+// and then in the branch name.
+func branchIssueKey(repo string, branchName string) (string, bool) {
+	entries, err := history.LoadBranches()
+	if err != nil {
+		utils.Log.Error().Err(err).Msg("Failed to read branch history")
+	}
+	for _, entry := range entries {
+		if entry.Repo == repo && entry.Branch == branchName {
+			return entry.IssueKey, true
+		}
+	}
+
+	match := issueKeyRegex.FindStringSubmatch(branchName)
+	if match == nil {
+		return "", false
+	}
+	return strings.ToUpper(match[1]), true
+}
+
+func openPullRequest(m model) (model, tea.Cmd) {
+	repo := m.currentRepo()
+	branchName, err := git_utils.CurrentBranch(repo)
+	if repo == "" || err != nil || branchName == "" {
+		return showToast(m, toastMsg{text: "Check out a ticket branch to create a pull request", isError: true})
+	}
+
+	remote := m.config.GetRemote()
+	base := git_utils.DefaultBranch(remote)
+	if branchName == base {
+		return showToast(m, toastMsg{text: "Check out a ticket branch to create a pull request", isError: true})
+	}
+	if !git_utils.RemoteBranchExists(remote, branchName) {
+		return showToast(m, toastMsg{text: fmt.Sprintf("Push %s to %s first", branchName, remote), isError: true})
+	}
+	issueKey, ok := branchIssueKey(repo, branchName)
+	if !ok {
+		return showToast(m, toastMsg{text: fmt.Sprintf("Couldn't tell which ticket %s is for", branchName), isError: true})
+	}
+
+	m.view = "pr"
+	m.prBranch = branchName
+	m.prBase = base
+	m.prForm = nil
+	m.prCreated = forge.CreatedPullRequest{}
+	m.prErr = nil
+	m.isPreparingPR = true
+	return m, tea.Batch(m.spinner.Tick, preparePullRequest(m.client, m.config, issueKey))
+}
+
+// preparePullRequest fetches the ticket and finds the forge before the form
+// is shown, so a missing token doesn't turn up after filling it in.
+func preparePullRequest(client *jira.Client, config utils.JiraBranchConfig, issueKey string) tea.Cmd {
+	return func() tea.Msg {
+		remoteURL, err := git_utils.RemoteURL(config.GetRemote())
+		if err != nil {
+			return prPreparedMsg{err: err}
+		}
+		remote, err := forge.ParseRemote(remoteURL, config.Forges)
+		if err != nil {
+			return prPreparedMsg{err: err}
+		}
+		api, err := forge.New(remote, config.Forges[remote.Host])
+		if err != nil {
+			return prPreparedMsg{err: err}
+		}
+
+		details, err := client.GetIssueDetails(issueKey)
+		return prPreparedMsg{details: details, forge: api, err: err}
+	}
+}
+
+// pullRequestBody fills in the template for the body of a pull request.
+func pullRequestBody(config utils.JiraBranchConfig, credentials jira.Credentials, details jira.IssueDetails, branchName string) string {
+	return utils.ExpandTemplate(config.GetPullRequestTemplate(), map[string]string{
+		"key":         details.Key,
+		"summary":     details.Summary,
+		"link":        credentials.IssueURL(details.Key),
+		"branch":      branchName,
+		"description": excerpt(details.Description, descriptionExcerptLength),
+	})
+}
+
+// excerpt shortens text to about limit characters, at the end of a word.
+func excerpt(text string, limit int) string {
+	text = strings.TrimSpace(text)
+	if runewidth.StringWidth(text) <= limit {
+		return text
+	}
+	cut := runewidth.Truncate(text, limit, "")
+	if i := strings.LastIndexAny(cut, " \n"); i > limit/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimSpace(cut) + "…"
+}
+
+func createPullRequestForm(m *model) *huh.Form {
+	title := fmt.Sprintf("%s: %s", m.prTicket.Key, m.prTicket.Summary)
+	body := pullRequestBody(m.config, m.credentials, m.prTicket, m.prBranch)
+	draft := true
+	linkOnIssue := true
+	m.prTitle = &title
+	m.prBody = &body
+	m.prDraft = &draft
+	m.prLinkOnIssue = &linkOnIssue
+	preview := *m
+
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Title").
+				Description(fmt.Sprintf("%s into %s", m.prBranch, m.prBase)).
+				Value(m.prTitle).
+				Validate(func(value string) error {
+					if strings.TrimSpace(value) == "" {
+						return fmt.Errorf("title is required")
+					}
+					return nil
+				}),
+			huh.NewText().
+				Title("Description").
+				Lines(8).
+				Value(m.prBody),
+			huh.NewConfirm().
+				Title("Open as a draft?").
+				Value(m.prDraft).
+				Affirmative("Yes").
+				Negative("No").
+				Inline(true),
+			huh.NewConfirm().
+				Title(fmt.Sprintf("Link it on %s?", m.prTicket.Key)).
+				Value(m.prLinkOnIssue).
+				Affirmative("Yes").
+				Negative("No").
+				Inline(true),
+			huh.NewNote().
+				Title("This will").
+				DescriptionFunc(func() string {
+					return viewPullRequestSteps(preview)
+				}, []any{m.prDraft, m.prLinkOnIssue}).
+				Next(true).
+				NextLabel("Confirm"),
+		),
+	).WithTheme(customTheme())
+}
+
+// pullRequestOperations are the updates to the issue once its pull request
+// is open, the link and what the automation rules ask for.
+func pullRequestOperations(m model, created forge.CreatedPullRequest, title string) []outbox.Operation {
+	operations := []outbox.Operation{}
+	if *m.prLinkOnIssue {
+		operations = append(operations, outbox.Operation{
+			Kind:      outbox.KindRemoteLink,
+			IssueKey:  m.prTicket.Key,
+			LinkURL:   created.URL,
+			LinkTitle: fmt.Sprintf("Pull request #%d: %s", created.Number, title),
+		})
+	}
+	return append(operations, automation.Operations(m.config.Automation, automation.PullRequestOpened, automation.Subject{
+		IssueKey: m.prTicket.Key,
+		Summary:  m.prTicket.Summary,
+		Branch:   m.prBranch,
+		Repo:     m.currentRepoName(),
+		URL:      created.URL,
+	})...)
+}
+
+// viewPullRequestSteps lists what confirming the pull request form does.
+func viewPullRequestSteps(m model) string {
+	kind := "pull request"
+	if *m.prDraft {
+		kind = "draft pull request"
+	}
+	lines := []string{fmt.Sprintf("• Open a %s from %s into %s", kind, m.prBranch, m.prBase)}
+	preview := forge.CreatedPullRequest{URL: "{url}"}
+	for _, op := range pullRequestOperations(m, preview, *m.prTitle) {
+		lines = append(lines, fmt.Sprintf("• %s: %s", op.IssueKey, op.Description()))
+	}
+	return gui.FaintWhiteText.Render(strings.Join(lines, "\n"))
+}
+
+func submitPullRequest(m model) tea.Cmd {
+	pr := forge.PullRequest{
+		Title: strings.TrimSpace(*m.prTitle),
+		Body:  *m.prBody,
+		Head:  m.prBranch,
+		Base:  m.prBase,
+		Draft: *m.prDraft,
+	}
+
+	return func() tea.Msg {
+		created, err := m.prForge.CreatePullRequest(pr)
+		if err != nil {
+			return prCreatedMsg{err: err}
+		}
+
+		msg := prCreatedMsg{created: created}
+		for _, op := range pullRequestOperations(m, created, pr.Title) {
+			queued, err := sendJiraOperation(m, op)
+			if err != nil {
+				msg.jiraErr = err
+				break
+			}
+			msg.queued = msg.queued || queued
+			msg.jiraUpdates++
+		}
+		return msg
+	}
+}
+
+func updatePullRequest(m model, msg tea.Msg) (model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case prPreparedMsg:
+		m.isPreparingPR = false
+		if msg.err != nil {
+			m.prErr = msg.err
+			return m, nil
+		}
+		m.prTicket = msg.details
+		m.prForge = msg.forge
+		m.prForm = createPullRequestForm(&m)
+		return m, m.prForm.Init()
+
+	case prCreatedMsg:
+		m.isCreatingPR = false
+		m.prErr = msg.err
+		m.prCreated = msg.created
+		if msg.err != nil {
+			return m, nil
+		}
+		toast := toastMsg{text: fmt.Sprintf("Updated %s", m.prTicket.Key)}
+		switch {
+		case msg.jiraErr != nil:
+			toast = toastMsg{text: fmt.Sprintf("Couldn't update %s: %s", m.prTicket.Key, msg.jiraErr), isError: true}
+		case msg.queued:
+			toast = toastMsg{text: fmt.Sprintf("Updates to %s are queued", m.prTicket.Key)}
+		case msg.jiraUpdates == 0:
+			return m, nil
+		}
+		return showToast(m, toast)
+
+	case tea.KeyMsg:
+		if m.isPreparingPR || m.isCreatingPR {
+			return m, nil
+		}
+		if key.Matches(msg, m.keys.Back) {
+			m.view = "list"
+			m.prForm = nil
+			return m, nil
+		}
+		if m.prCreated.URL != "" {
+			switch {
+			case key.Matches(msg, m.keys.Open):
+				return m, openURL(m.prCreated.URL, "the pull request")
+			case key.Matches(msg, m.keys.CopyLink):
+				return m, copyToClipboard(m.prCreated.URL, "link")
+			}
+			return m, nil
+		}
+	}
+
+	if m.prForm == nil || m.prCreated.URL != "" || m.prErr != nil {
+		return m, nil
+	}
+
+	form, cmd := m.prForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.prForm = f
+		if m.prForm.State == huh.StateCompleted {
+			m.isCreatingPR = true
+			return m, tea.Batch(m.spinner.Tick, submitPullRequest(m))
+		}
+	}
+	return m, cmd
+}
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshwrn/jira-branch/internal/jira"
+	"github.com/joshwrn/jira-branch/internal/utils"
+)
+
+// previewDelay is how long the cursor has to rest on a ticket before its
+// details are fetched, so scrolling through the list doesn't fire a request
+// per row.
+const previewDelay = 250 * time.Millisecond
+
+const (
+	minPreviewWidth = 32
+	maxPreviewWidth = 64
+	// the list needs this much room next to the preview
+	minListWidth = 72
+)
+
+type preview struct {
+	details   jira.IssueDetails
+	err       error
+	isLoading bool
+}
+
+type previewDebounceMsg struct {
+	id  int
+	key string
+}
+
+type previewMsg struct {
+	key     string
+	details jira.IssueDetails
+	err     error
+}
+
+// previewWidth is the width of the preview pane including its border, or 0
+// when it's hidden or the terminal is too narrow for it.
+func (m model) previewWidth() int {
+	if !m.showPreview || m.tab != "tickets" {
+		return 0
+	}
+	width := max(minPreviewWidth, min(maxPreviewWidth, m.width/3))
+	if m.width-width < minListWidth {
+		return 0
+	}
+	return width
+}
+
+// schedulePreview fetches the details of the highlighted ticket once the
+// cursor stops moving, unless they are cached already. Failed fetches stay
+// around to show the error, but are tried again.
+func (m model) schedulePreview() (model, tea.Cmd) {
+	if m.previewWidth() == 0 || m.client == nil {
+		m.previewKey = ""
+		return m, nil
+	}
+
+	key := m.selectedTicketKey()
+	if key == "" || key == m.previewKey {
+		return m, nil
+	}
+	m.previewKey = key
+	if m.isPreviewCached(key) {
+		return m, nil
+	}
+
+	m.previewID++
+	id := m.previewID
+	return m, tea.Tick(previewDelay, func(time.Time) tea.Msg {
+		return previewDebounceMsg{id: id, key: key}
+	})
+}
+
+func (m model) isPreviewCached(key string) bool {
+	preview, ok := m.previews[key]
+	return ok && preview.err == nil
+}
+
+func fetchPreview(client *jira.Client, key string) tea.Cmd {
+	return func() tea.Msg {
+		details, err := client.GetIssueDetails(key)
+		if err != nil {
+			utils.Log.Error().Err(err).Str("key", key).Msg("Failed to fetch issue details")
+		}
+		return previewMsg{key: key, details: details, err: err}
+	}
+}
+
+func updatePreviewDebounce(m model, msg previewDebounceMsg) (model, tea.Cmd) {
+	// the cursor moved on in the meantime
+	if msg.id != m.previewID || m.client == nil {
+		return m, nil
+	}
+	if m.isPreviewCached(msg.key) {
+		return m, nil
+	}
+
+	if m.previews == nil {
+		m.previews = map[string]preview{}
+	}
+	m.previews[msg.key] = preview{isLoading: true}
+	return m, fetchPreview(m.client, msg.key)
+}
+
+func updatePreview(m model, msg previewMsg) (model, tea.Cmd) {
+	if m.previews == nil {
+		m.previews = map[string]preview{}
+	}
+	m.previews[msg.key] = preview{details: msg.details, err: msg.err}
+	return m, nil
+}
+
+func togglePreview(m model) (model, tea.Cmd) {
+	m.showPreview = !m.showPreview
+	m.previewKey = ""
+	m.updateTableSize()
+	return m.schedulePreview()
+}
+
+// clearPreviews drops the cached details after the tickets were refreshed,
+// since they may have changed as well.
+func (m model) clearPreviews() (model, tea.Cmd) {
+	m.previews = map[string]preview{}
+	m.previewKey = ""
+	return m.schedulePreview()
+}
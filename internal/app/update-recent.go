@@ -0,0 +1,274 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshwrn/jira-branch/internal/automation"
+	"github.com/joshwrn/jira-branch/internal/git_utils"
+	"github.com/joshwrn/jira-branch/internal/gui"
+	"github.com/joshwrn/jira-branch/internal/history"
+	"github.com/joshwrn/jira-branch/internal/jira"
+	"github.com/joshwrn/jira-branch/internal/outbox"
+	"github.com/joshwrn/jira-branch/internal/utils"
+)
+
+type recentMsg []history.BranchEntry
+
+type switchedBranchMsg struct {
+	entry history.BranchEntry
+	err   error
+}
+
+type deletedBranchMsg struct {
+	entry   history.BranchEntry
+	jiraErr error
+	err     error
+}
+
+func loadRecent() tea.Cmd {
+	return func() tea.Msg {
+		entries, err := history.LoadBranches()
+		if err != nil {
+			utils.Log.Error().Err(err).Msg("Failed to read branch history")
+		}
+		return recentMsg(history.RecentBranches(entries))
+	}
+}
+
+// recordBranch adds a branch the tool created or checked out in the current
+// repository to the history.
+func recordBranch(ticket jira.JiraTicketsMsg, branchName string, created bool) {
+	repo, err := utils.GetGitRoot()
+	if err != nil {
+		utils.Log.Error().Err(err).Msg("Failed to find the repository for the branch history")
+		return
+	}
+
+	action := history.BranchCheckedOut
+	if created {
+		action = history.BranchCreated
+	}
+	err = history.AddBranch(history.BranchEntry{
+		IssueKey: ticket.Key,
+		Summary:  ticket.Summary,
+		Branch:   branchName,
+		Repo:     repo,
+		Action:   action,
+	})
+	if err != nil {
+		utils.Log.Error().Err(err).Msg("Failed to save branch history")
+	}
+}
+
+func (m model) currentRepo() string {
+	repo, err := utils.GetGitRoot()
+	if err != nil {
+		return ""
+	}
+	return repo
+}
+
+// currentRepoName is the name of the repository's folder, or empty outside
+// of a repository.
+func (m model) currentRepoName() string {
+	repo := m.currentRepo()
+	if repo == "" {
+		return ""
+	}
+	return filepath.Base(repo)
+}
+
+func (m model) selectedRecent() (history.BranchEntry, bool) {
+	cursor := m.recentTable.Cursor()
+	if cursor < 0 || cursor >= len(m.recentEntries) {
+		return history.BranchEntry{}, false
+	}
+	return m.recentEntries[cursor], true
+}
+
+func (m *model) updateRecentTable() {
+	currentRepo := m.currentRepo()
+
+	rows := []gui.Row{}
+	for _, entry := range m.recentEntries {
+		repo := filepath.Base(entry.Repo)
+		if entry.Repo == currentRepo {
+			repo += " (here)"
+		}
+		rows = append(rows, gui.Row{
+			{Text: entry.IssueKey},
+			{Text: entry.Summary},
+			{Text: entry.Branch},
+			{Text: repo},
+			{Text: utils.FormatTimeAgo(entry.At)},
+		})
+	}
+
+	keyWidth := 10
+	repoWidth := 18
+	whenWidth := 15
+	flexible := max(30, m.width-keyWidth-repoWidth-whenWidth-12)
+	summaryWidth := flexible / 2
+	branchWidth := flexible - summaryWidth
+
+	m.recentTable.SetColumns([]gui.Column{
+		{Title: "Key", Width: keyWidth},
+		{Title: "Summary", Width: summaryWidth},
+		{Title: "Branch", Width: branchWidth},
+		{Title: "Repository", Width: repoWidth},
+		{Title: "When", Width: whenWidth},
+	})
+	m.recentTable.SetRows(rows)
+	m.recentTable.SetWidth(m.width - 2)
+	m.recentTable.SetHeight(m.height - 4)
+}
+
+func switchTab(m model) (model, tea.Cmd) {
+	if m.tab == "recent" {
+		m.tab = "tickets"
+		return m, nil
+	}
+	m.tab = "recent"
+	m.updateRecentTable()
+	return m, loadRecent()
+}
+
+// checkoutRecent switches straight to a branch from the history. In this
+// repository that is the end of the job, like with the form, while other
+// repositories are switched in place.
+func checkoutRecent(entry history.BranchEntry) tea.Cmd {
+	return func() tea.Msg {
+		err := git_utils.SwitchBranch(entry.Repo, entry.Branch)
+		if err == nil {
+			err = history.AddBranch(history.BranchEntry{
+				IssueKey: entry.IssueKey,
+				Summary:  entry.Summary,
+				Branch:   entry.Branch,
+				Repo:     entry.Repo,
+				Action:   history.BranchCheckedOut,
+			})
+			if err != nil {
+				utils.Log.Error().Err(err).Msg("Failed to save branch history")
+				err = nil
+			}
+			trackBranch(entry.IssueKey, entry.Summary, entry.Repo, entry.Branch)
+		}
+		return switchedBranchMsg{entry: entry, err: err}
+	}
+}
+
+func updateSwitchedBranch(m model, msg switchedBranchMsg) (model, tea.Cmd) {
+	if msg.err != nil {
+		return showToast(m, toastMsg{text: msg.err.Error(), isError: true})
+	}
+	if msg.entry.Repo == m.currentRepo() {
+		return m, tea.Quit
+	}
+	m, cmd := showToast(m, toastMsg{
+		text: fmt.Sprintf("Checked out %s in %s", msg.entry.Branch, filepath.Base(msg.entry.Repo)),
+	})
+	return m, tea.Batch(cmd, loadRecent())
+}
+
+// deleteOperations are what the automation rules do when a branch is deleted.
+func deleteOperations(m model, entry history.BranchEntry) []outbox.Operation {
+	return automation.Operations(m.config.Automation, automation.BranchDeleted, automation.Subject{
+		IssueKey: entry.IssueKey,
+		Summary:  entry.Summary,
+		Branch:   entry.Branch,
+		Repo:     filepath.Base(entry.Repo),
+	})
+}
+
+// confirmDelete asks to press the key again, saying what will happen.
+func confirmDelete(m model, entry history.BranchEntry) (model, tea.Cmd) {
+	m.pendingDelete = entry
+	text := fmt.Sprintf("Press %s again to delete %s", m.keys.DeleteBranch.Help().Key, entry.Branch)
+	for _, op := range deleteOperations(m, entry) {
+		text += fmt.Sprintf(", %s: %s", op.IssueKey, op.Description())
+	}
+	return showToast(m, toastMsg{text: text})
+}
+
+func deleteRecent(m model, entry history.BranchEntry) tea.Cmd {
+	operations := deleteOperations(m, entry)
+	return func() tea.Msg {
+		if err := git_utils.DeleteBranch(entry.Repo, entry.Branch); err != nil {
+			return deletedBranchMsg{entry: entry, err: err}
+		}
+		if err := history.RemoveBranch(entry.Repo, entry.Branch); err != nil {
+			utils.Log.Error().Err(err).Msg("Failed to save branch history")
+		}
+
+		msg := deletedBranchMsg{entry: entry}
+		for _, op := range operations {
+			if _, err := sendJiraOperation(m, op); err != nil {
+				msg.jiraErr = err
+				break
+			}
+		}
+		return msg
+	}
+}
+
+func updateDeletedBranch(m model, msg deletedBranchMsg) (model, tea.Cmd) {
+	toast := toastMsg{text: fmt.Sprintf("Deleted %s", msg.entry.Branch)}
+	switch {
+	case msg.err != nil:
+		toast = toastMsg{text: msg.err.Error(), isError: true}
+	case msg.jiraErr != nil:
+		toast = toastMsg{
+			text:    fmt.Sprintf("Deleted %s, but couldn't update %s: %s", msg.entry.Branch, msg.entry.IssueKey, msg.jiraErr),
+			isError: true,
+		}
+	}
+	m, cmd := showToast(m, toast)
+	return m, tea.Batch(cmd, loadRecent())
+}
+
+func updateRecent(m model, msg tea.Msg) (model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		return updateRecentMouse(m, msg)
+	case tea.KeyMsg:
+		entry, hasEntry := m.selectedRecent()
+		pendingDelete := m.pendingDelete
+		m.pendingDelete = history.BranchEntry{}
+		if hasEntry && key.Matches(msg, m.keys.DeleteBranch) {
+			if pendingDelete.Repo == entry.Repo && pendingDelete.Branch == entry.Branch {
+				return m, deleteRecent(m, entry)
+			}
+			return confirmDelete(m, entry)
+		}
+		if hasEntry && key.Matches(msg, m.keys.CopyBranch) {
+			return m, copyToClipboard(entry.Branch, "branch name")
+		}
+		if hasEntry {
+			ticket := jira.JiraTicketsMsg{Key: entry.IssueKey, Summary: entry.Summary}
+			if cmd, ok := ticketAction(m, msg, ticket); ok {
+				return m, cmd
+			}
+		}
+
+		switch {
+		case key.Matches(msg, m.keys.SwitchTab), key.Matches(msg, m.keys.Back):
+			return switchTab(m)
+		case key.Matches(msg, m.keys.Help):
+			m.showHelp = true
+			return m, nil
+		case key.Matches(msg, m.keys.Refresh):
+			return m, loadRecent()
+		case key.Matches(msg, m.keys.Select):
+			if hasEntry {
+				return m, checkoutRecent(entry)
+			}
+		}
+	}
+
+	updatedTable, cmd := m.recentTable.Update(msg)
+	m.recentTable = updatedTable
+	return m, cmd
+}
@@ -1,104 +1,227 @@
 package app
 
 import (
-	"strings"
+	"sort"
+	"time"
 
-	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/joshwrn/jira-branch/internal/fuzzy"
+	"github.com/joshwrn/jira-branch/internal/gui"
 	"github.com/joshwrn/jira-branch/internal/jira"
+	"github.com/joshwrn/jira-branch/internal/query"
 	"github.com/joshwrn/jira-branch/internal/utils"
 )
 
-func keyIncludesSearch(search string, ticket jira.JiraTicketsMsg) bool {
-	keyMap := map[string]string{
-		"key":     ticket.Key,
-		"summary": ticket.Summary,
-		"type":    ticket.Type,
-		"status":  ticket.Status,
+type ticketMatch struct {
+	ticket jira.JiraTicketsMsg
+	score  int
+	// matched rune indices by field name
+	highlights map[string][]int
+}
+
+// searchColumns are the columns a search can name, which are the ones in the
+// table and the ones every ticket has.
+func (m model) searchColumns() map[string]ticketColumn {
+	columns := map[string]ticketColumn{}
+	for _, id := range defaultColumns {
+		column, _ := builtinColumn(id, m.config)
+		column.id = id
+		columns[id] = column
 	}
-	for _, value := range keyMap {
-		if strings.Contains(strings.ToLower(value), strings.ToLower(search)) {
-			return true
-		}
+	for _, column := range m.columns {
+		columns[column.id] = column
 	}
-	return false
+	return columns
 }
 
-func filterTickets(m *model) {
-	searchTerm := m.searchInput.Value()
-	filteredTickets := []jira.JiraTicketsMsg{}
-
-	for _, ticket := range m.allTickets {
-		if keyIncludesSearch(searchTerm, ticket) {
-			filteredTickets = append(filteredTickets, ticket)
-		}
+func searchFields(columns map[string]ticketColumn) map[string]query.FieldKind {
+	fields := map[string]query.FieldKind{}
+	for id, column := range columns {
+		fields[id] = column.searchKind()
 	}
+	return fields
+}
+
+// ticketSubject exposes a ticket's fields to the search query.
+type ticketSubject struct {
+	ticket  jira.JiraTicketsMsg
+	columns map[string]ticketColumn
+}
 
-	utils.Log.Info().Msgf("filteredTickets: %v", len(filteredTickets))
+func (t ticketSubject) Text(field string) string {
+	column, ok := t.columns[field]
+	if !ok {
+		return ""
+	}
+	return column.value(t.ticket)
+}
 
-	m.tickets = filteredTickets
-	columns := []table.Column{
-		{Title: "Key", Width: 0},
-		{Title: "Type", Width: 0},
-		{Title: "Summary", Width: 0},
-		{Title: "Status", Width: 0},
-		{Title: "Created", Width: 0},
+func (t ticketSubject) Time(field string) (time.Time, bool) {
+	column, ok := t.columns[field]
+	if !ok {
+		return time.Time{}, false
 	}
+	value, err := utils.ParseJiraTime(column.value(t.ticket))
+	return value, err == nil
+}
 
-	rows := []table.Row{}
-	for _, ticket := range m.tickets {
-		rows = append(rows, table.Row{ticket.Key, ticket.Type, ticket.Summary, ticket.Status, utils.FormatRelativeTime(ticket.Created)})
+// MatchText fuzzy matches a free text term against the ticket's fields and
+// keeps the field it matched best.
+func (t ticketSubject) MatchText(term string) (query.Match, bool) {
+	bestField := ""
+	best := fuzzy.Result{}
+	for _, field := range []string{"key", "summary", "type", "status"} {
+		result, ok := fuzzy.Match(term, t.Text(field))
+		if !ok {
+			continue
+		}
+		if field == "key" && result.Positions[0] == 0 {
+			result.Score += keyPrefixBonus
+		}
+		if bestField == "" || result.Score > best.Score {
+			bestField, best = field, result
+		}
+	}
+	if bestField == "" {
+		return query.Match{}, false
 	}
+	return query.Match{
+		Score:      best.Score,
+		Highlights: map[string][]int{bestField: best.Positions},
+	}, true
+}
 
-	t := table.New(
-		table.WithColumns(columns),
-		table.WithRows(rows),
-		table.WithFocused(true),
-	)
+const keyPrefixBonus = 24
 
-	s := table.DefaultStyles()
-	s.Header = s.Header.
+func createTicketTable() gui.Table {
+	t := gui.NewTable()
+	t.Styles.Header = t.Styles.Header.
 		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color("8")).
+		BorderForeground(gui.CurrentTheme.Border).
 		BorderBottom(true).
 		Bold(false)
-	s.Selected = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("12")).
-		Background(lipgloss.Color("0")).
-		Bold(false)
+	t.Styles.Selected = gui.SelectedStyle().Bold(false)
+	t.Styles.Highlight = lipgloss.NewStyle().
+		Foreground(gui.CurrentTheme.Warning).
+		Bold(true).
+		Underline(gui.CurrentTheme.Monochrome)
+	return t
+}
 
-	t.SetStyles(s)
+func filterTickets(m *model) {
+	columns := m.searchColumns()
+	searchQuery, err := query.Parse(m.searchInput.Value(), searchFields(columns))
+	if err != nil {
+		// keep filtering with the last query that made sense
+		m.searchErr = err
+	} else {
+		m.searchErr = nil
+		m.searchQuery = searchQuery
+	}
+
+	matches := []ticketMatch{}
+	for _, ticket := range m.allTickets {
+		if match, ok := m.searchQuery.Match(ticketSubject{ticket: ticket, columns: columns}); ok {
+			matches = append(matches, ticketMatch{
+				ticket:     ticket,
+				score:      match.Score,
+				highlights: match.Highlights,
+			})
+		}
+	}
+	if sortColumn, ok := m.findColumn(m.sort.Column); ok {
+		sort.SliceStable(matches, func(i, j int) bool {
+			c := compareTickets(matches[i].ticket, matches[j].ticket, sortColumn)
+			if m.sort.Descending {
+				return c > 0
+			}
+			return c < 0
+		})
+	} else {
+		// best matches first, Jira's order for ties and an empty search
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].score > matches[j].score
+		})
+	}
+
+	utils.Log.Info().Msgf("filteredTickets: %v", len(matches))
 
-	m.list = t
+	if m.treeMode {
+		m.treeRows = buildTree(matches, m.collapsed)
+	} else {
+		m.treeRows = []treeRow{}
+		for _, match := range matches {
+			m.treeRows = append(m.treeRows, treeRow{match: match})
+		}
+	}
+
+	m.tickets = []jira.JiraTicketsMsg{}
+	rows := []gui.Row{}
+	treeColumn := m.treeColumn()
+	for _, treeRow := range m.treeRows {
+		match := treeRow.match
+		m.tickets = append(m.tickets, match.ticket)
+		row := gui.Row{}
+		if m.hasSelection() {
+			marker := gui.Cell{Text: " "}
+			if m.selected[match.ticket.Key] {
+				marker.Text = "●"
+			}
+			row = append(row, marker)
+		}
+		for i, column := range m.columns {
+			cell := gui.Cell{
+				Text:       column.text(match.ticket),
+				Highlights: match.highlights[column.id],
+				Faint:      treeRow.isPlaceholder,
+			}
+			if m.treeMode && i == treeColumn {
+				cell = m.treeCell(treeRow, cell)
+			}
+			row = append(row, cell)
+		}
+		rows = append(rows, row)
+	}
+
+	m.list.SetRows(rows)
 	m.updateTableSize()
 }
 
 func updateSearch(m model, msg tea.Msg) (model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "enter":
+		switch {
+		case key.Matches(msg, m.keys.Confirm):
+			if m.searchErr != nil {
+				return m, nil
+			}
 			m.showSearch = false
 			m.updateTableSize()
 			m.search = m.searchInput.Value()
 			m.list.GotoTop()
 			return m, nil
-		case "esc":
+		case key.Matches(msg, m.keys.Back):
 			m.showSearch = false
 			m.searchInput.SetValue("")
 			m.search = ""
 			filterTickets(&m)
 			m.list.GotoTop()
-			return m, nil
+			return m.schedulePreview()
 		}
 	}
 
 	if !m.isLoading && m.isLoggedIn {
+		previousSearch := m.searchInput.Value()
 		updatedSearchInput, cmd := m.searchInput.Update(msg)
 		m.searchInput = updatedSearchInput
-		filterTickets(&m)
-		return m, cmd
+		if m.searchInput.Value() != previousSearch {
+			filterTickets(&m)
+			m.list.GotoTop()
+		}
+		m, previewCmd := m.schedulePreview()
+		return m, tea.Batch(cmd, previewCmd)
 	}
 
 	return m, nil
@@ -0,0 +1,145 @@
+package app
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshwrn/jira-branch/internal/jira"
+	"github.com/joshwrn/jira-branch/internal/prefs"
+	"github.com/joshwrn/jira-branch/internal/utils"
+)
+
+func (m *model) loadSort() {
+	m.sort = prefs.LoadSort(m.ticketsQuery())
+	if _, ok := m.findColumn(m.sort.Column); !ok {
+		// the column was removed from the config
+		m.sort = prefs.Sort{}
+	}
+}
+
+func saveSort(jql string, sort prefs.Sort) tea.Cmd {
+	return func() tea.Msg {
+		if err := prefs.SaveSort(jql, sort); err != nil {
+			utils.Log.Error().Err(err).Msg("Failed to save sort preferences")
+		}
+		return nil
+	}
+}
+
+// cycleSort sorts by the next column, going back to Jira's order after the
+// last one.
+func cycleSort(m model) (model, tea.Cmd) {
+	index := slices.IndexFunc(m.columns, func(column ticketColumn) bool {
+		return column.id == m.sort.Column
+	})
+	if index == len(m.columns)-1 {
+		m.sort = prefs.Sort{}
+	} else {
+		column := m.columns[index+1]
+		// newest first is what you want for dates
+		m.sort = prefs.Sort{Column: column.id, Descending: column.kind == timeColumn}
+	}
+	return applySort(m)
+}
+
+// sortByColumn sorts by a column, or flips the direction when the list is
+// sorted by it already.
+func sortByColumn(m model, column ticketColumn) (model, tea.Cmd) {
+	if m.sort.Column == column.id {
+		return toggleSortDirection(m)
+	}
+	m.sort = prefs.Sort{Column: column.id, Descending: column.kind == timeColumn}
+	return applySort(m)
+}
+
+func toggleSortDirection(m model) (model, tea.Cmd) {
+	if m.sort.Column == "" {
+		return m, nil
+	}
+	m.sort.Descending = !m.sort.Descending
+	return applySort(m)
+}
+
+func applySort(m model) (model, tea.Cmd) {
+	selectedKey := m.selectedTicketKey()
+	filterTickets(&m)
+	m.selectTicket(selectedKey)
+	return m, saveSort(m.ticketsQuery(), m.sort)
+}
+
+// sortTitle marks the column the list is sorted by.
+func (m model) sortTitle(column string, title string) string {
+	if m.sort.Column != column {
+		return title
+	}
+	if m.sort.Descending {
+		return title + " ▼"
+	}
+	return title + " ▲"
+}
+
+func compareTickets(a, b jira.JiraTicketsMsg, column ticketColumn) int {
+	valueA, valueB := column.value(a), column.value(b)
+	switch column.kind {
+	case keyColumn:
+		return compareIssueKeys(valueA, valueB)
+	case numberColumn:
+		return compareNumbers(valueA, valueB)
+	case timeColumn, dateColumn:
+		return compareJiraTimes(valueA, valueB)
+	}
+	return compareFold(valueA, valueB)
+}
+
+func compareFold(a, b string) int {
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// compareIssueKeys orders keys by project and then by number, so PRJ-9 comes
+// before PRJ-10.
+func compareIssueKeys(a, b string) int {
+	projectA, numberA, _ := strings.Cut(a, "-")
+	projectB, numberB, _ := strings.Cut(b, "-")
+	if c := strings.Compare(projectA, projectB); c != 0 {
+		return c
+	}
+
+	intA, errA := strconv.Atoi(numberA)
+	intB, errB := strconv.Atoi(numberB)
+	if errA != nil || errB != nil {
+		return strings.Compare(numberA, numberB)
+	}
+	return cmp.Compare(intA, intB)
+}
+
+func compareJiraTimes(a, b string) int {
+	timeA, errA := utils.ParseJiraTime(a)
+	timeB, errB := utils.ParseJiraTime(b)
+	switch {
+	case errA != nil && errB != nil:
+		return 0
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	}
+	return timeA.Compare(timeB)
+}
+
+// compareNumbers puts tickets without a number first, like compareJiraTimes.
+func compareNumbers(a, b string) int {
+	numberA, errA := strconv.ParseFloat(a, 64)
+	numberB, errB := strconv.ParseFloat(b, 64)
+	switch {
+	case errA != nil && errB != nil:
+		return 0
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	}
+	return cmp.Compare(numberA, numberB)
+}
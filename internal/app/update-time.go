@@ -0,0 +1,226 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshwrn/jira-branch/internal/gui"
+	"github.com/joshwrn/jira-branch/internal/outbox"
+	"github.com/joshwrn/jira-branch/internal/timetrack"
+	"github.com/joshwrn/jira-branch/internal/utils"
+)
+
+type timeMsg struct {
+	totals []timetrack.Total
+	status string
+	err    error
+}
+
+// trackBranch starts tracking time on a ticket's branch that was just
+// checked out.
+func trackBranch(issueKey string, summary string, repo string, branchName string) {
+	err := timetrack.Start(timetrack.Session{
+		IssueKey: issueKey,
+		Summary:  summary,
+		Branch:   branchName,
+		Repo:     repo,
+	})
+	if err != nil {
+		utils.Log.Error().Err(err).Msg("Failed to start time tracking")
+	}
+}
+
+func loadTime() tea.Cmd {
+	return func() tea.Msg {
+		if err := timetrack.Reconcile(); err != nil {
+			utils.Log.Error().Err(err).Msg("Failed to reconcile time sessions")
+		}
+		sessions, err := timetrack.Load()
+		if err != nil {
+			utils.Log.Error().Err(err).Msg("Failed to read time sessions")
+		}
+		return timeMsg{totals: timetrack.Totals(sessions, time.Now())}
+	}
+}
+
+func discardTime(total timetrack.Total) tea.Cmd {
+	return func() tea.Msg {
+		err := timetrack.MarkLogged(total.IssueKey, total.At)
+		msg := loadTime()().(timeMsg)
+		msg.err = err
+		if err == nil {
+			msg.status = fmt.Sprintf("Discarded the time on %s", total.IssueKey)
+		}
+		return msg
+	}
+}
+
+func submitWorklog(m model, total timetrack.Total, timeSpent time.Duration, comment string) tea.Cmd {
+	return func() tea.Msg {
+		queued, err := sendJiraOperation(m, outbox.Operation{
+			Kind:      outbox.KindWorklog,
+			IssueKey:  total.IssueKey,
+			TimeSpent: timeSpent,
+			Started:   total.Started,
+			Comment:   comment,
+		})
+		if err != nil {
+			return timeMsg{totals: m.timeTotals, err: err}
+		}
+		if err := timetrack.MarkLogged(total.IssueKey, total.At); err != nil {
+			utils.Log.Error().Err(err).Msg("Failed to mark time as logged")
+		}
+
+		msg := loadTime()().(timeMsg)
+		msg.status = fmt.Sprintf("Logged %s on %s", timetrack.FormatDuration(timeSpent), total.IssueKey)
+		if queued {
+			msg.status = fmt.Sprintf("Queued %s on %s", timetrack.FormatDuration(timeSpent), total.IssueKey)
+		}
+		return msg
+	}
+}
+
+func openTime(m model) (model, tea.Cmd) {
+	m.view = "time"
+	m.timeStatus = ""
+	m.timeErr = nil
+	m.worklogForm = nil
+	return m, loadTime()
+}
+
+func (m *model) updateTimeTable() {
+	rows := []table.Row{}
+	for _, total := range m.timeTotals {
+		spent := timetrack.FormatDuration(total.Duration)
+		if total.IsRunning {
+			spent += " ●"
+		}
+		rows = append(rows, table.Row{
+			total.IssueKey,
+			total.Summary,
+			spent,
+			fmt.Sprintf("%d", total.Sessions),
+			utils.FormatTimeAgo(total.Started),
+		})
+	}
+
+	if m.timeTable.Columns() == nil {
+		m.timeTable = table.New(table.WithFocused(true))
+		m.timeTable.KeyMap = m.keys.tableKeyMap()
+		s := table.DefaultStyles()
+		s.Header = s.Header.
+			BorderStyle(lipgloss.NormalBorder()).
+			BorderForeground(gui.CurrentTheme.Border).
+			BorderBottom(true).
+			Bold(false)
+		s.Selected = gui.SelectedStyle().Bold(false)
+		m.timeTable.SetStyles(s)
+	}
+
+	keyWidth := 12
+	spentWidth := 10
+	sessionsWidth := 8
+	sinceWidth := 15
+	summaryWidth := max(20, m.width-keyWidth-spentWidth-sessionsWidth-sinceWidth-12)
+
+	m.timeTable.SetColumns([]table.Column{
+		{Title: "Issue", Width: keyWidth},
+		{Title: "Summary", Width: summaryWidth},
+		{Title: "Time", Width: spentWidth},
+		{Title: "Sessions", Width: sessionsWidth},
+		{Title: "Since", Width: sinceWidth},
+	})
+	m.timeTable.SetRows(rows)
+	m.timeTable.SetWidth(m.width - 2)
+	m.timeTable.SetHeight(m.height - 5)
+}
+
+func (m model) selectedTotal() (timetrack.Total, bool) {
+	cursor := m.timeTable.Cursor()
+	if cursor < 0 || cursor >= len(m.timeTotals) {
+		return timetrack.Total{}, false
+	}
+	return m.timeTotals[cursor], true
+}
+
+func createWorklogForm(m *model, total timetrack.Total) *huh.Form {
+	timeSpent := timetrack.FormatDuration(total.Duration)
+	comment := ""
+	m.worklogTotal = total
+	m.worklogTimeSpent = &timeSpent
+	m.worklogComment = &comment
+
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title(fmt.Sprintf("Time spent on %s", total.IssueKey)).
+				Description(total.Summary).
+				Value(m.worklogTimeSpent).
+				Validate(func(value string) error {
+					_, err := timetrack.ParseDuration(value)
+					return err
+				}),
+			huh.NewText().
+				Title("Comment").
+				Value(m.worklogComment),
+		),
+	).WithTheme(customTheme())
+}
+
+func updateWorklogForm(m model, msg tea.Msg) (model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keys.Back) {
+		m.worklogForm = nil
+		return m, nil
+	}
+
+	form, cmd := m.worklogForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.worklogForm = f
+		if m.worklogForm.State == huh.StateCompleted {
+			m.worklogForm = nil
+			timeSpent, _ := timetrack.ParseDuration(*m.worklogTimeSpent)
+			m.timeStatus = fmt.Sprintf("Logging work on %s...", m.worklogTotal.IssueKey)
+			m.timeErr = nil
+			comment := strings.TrimSpace(*m.worklogComment)
+			return m, submitWorklog(m, m.worklogTotal, timeSpent, comment)
+		}
+	}
+	return m, cmd
+}
+
+func updateTime(m model, msg tea.Msg) (model, tea.Cmd) {
+	if m.worklogForm != nil {
+		return updateWorklogForm(m, msg)
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Back):
+			m.view = "list"
+			m.timeStatus = ""
+			return m, nil
+		case key.Matches(msg, m.keys.Refresh):
+			return m, loadTime()
+		case key.Matches(msg, m.keys.Select):
+			if total, ok := m.selectedTotal(); ok {
+				m.worklogForm = createWorklogForm(&m, total)
+				return m, m.worklogForm.Init()
+			}
+		case key.Matches(msg, m.keys.Drop):
+			if total, ok := m.selectedTotal(); ok {
+				return m, discardTime(total)
+			}
+		}
+	}
+
+	updatedTable, cmd := m.timeTable.Update(msg)
+	m.timeTable = updatedTable
+	return m, cmd
+}
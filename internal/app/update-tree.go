@@ -0,0 +1,203 @@
+package app
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshwrn/jira-branch/internal/git_utils"
+	"github.com/joshwrn/jira-branch/internal/gui"
+	"github.com/joshwrn/jira-branch/internal/jira"
+)
+
+// treeRow is a ticket in tree mode, where subtasks are listed under their
+// story and stories under their epic.
+type treeRow struct {
+	match ticketMatch
+	depth int
+	// key of the row this one is listed under
+	parent      string
+	hasChildren bool
+	// a parent that isn't in the list itself, shown to group its children
+	isPlaceholder bool
+}
+
+// treeColumn is the column that shows the nesting, the key if it's shown.
+func (m model) treeColumn() int {
+	for i, column := range m.columns {
+		if column.id == "key" {
+			return i
+		}
+	}
+	return 0
+}
+
+// treeCell indents a cell by the depth of its row, behind a marker for rows
+// that can be expanded or collapsed.
+func (m model) treeCell(row treeRow, cell gui.Cell) gui.Cell {
+	marker := "  "
+	if row.hasChildren && m.collapsed[row.match.ticket.Key] {
+		marker = "▸ "
+	} else if row.hasChildren {
+		marker = "▾ "
+	}
+	prefix := strings.Repeat("  ", row.depth) + marker
+
+	offset := utf8.RuneCountInString(prefix)
+	highlights := make([]int, len(cell.Highlights))
+	for i, index := range cell.Highlights {
+		highlights[i] = index + offset
+	}
+	cell.Text = prefix + cell.Text
+	cell.Highlights = highlights
+	return cell
+}
+
+// treeLayoutColumns widens the tree column by the deepest indentation, so
+// nested keys aren't cut off.
+func (m model) treeLayoutColumns() []ticketColumn {
+	if !m.treeMode || len(m.columns) == 0 {
+		return m.columns
+	}
+	depth := 0
+	for _, row := range m.treeRows {
+		depth = max(depth, row.depth)
+	}
+	columns := append([]ticketColumn{}, m.columns...)
+	columns[m.treeColumn()].minWidth += 2 * (depth + 1)
+	return columns
+}
+
+func (m model) branchNaming() git_utils.BranchNaming {
+	return git_utils.BranchNaming{ParentForSubtasks: m.config.ParentBranchForSubtasks}
+}
+
+// buildTree nests the matches under their parents, keeping the order of the
+// matches among siblings. Parents that aren't in the list are added in front
+// of their first child, so children that got filtered away from their parent
+// are still grouped.
+func buildTree(matches []ticketMatch, collapsed map[string]bool) []treeRow {
+	byKey := map[string]ticketMatch{}
+	for _, match := range matches {
+		byKey[match.ticket.Key] = match
+	}
+
+	parentOf := map[string]string{}
+	for _, match := range matches {
+		if match.ticket.Parent != "" {
+			parentOf[match.ticket.Key] = match.ticket.Parent
+		}
+		// subtasks always know their parent, this is for older Jira sites
+		for _, subtask := range match.ticket.Subtasks {
+			if _, ok := parentOf[subtask]; !ok {
+				parentOf[subtask] = match.ticket.Key
+			}
+		}
+	}
+
+	roots := []string{}
+	children := map[string][]string{}
+	placeholders := map[string]bool{}
+	for _, match := range matches {
+		key := match.ticket.Key
+		parent, hasParent := parentOf[key]
+		if !hasParent {
+			roots = append(roots, key)
+			continue
+		}
+		if _, ok := byKey[parent]; !ok {
+			byKey[parent] = ticketMatch{ticket: jira.JiraTicketsMsg{
+				Key:     parent,
+				Summary: match.ticket.ParentSummary,
+				Type:    match.ticket.ParentType,
+			}}
+			placeholders[parent] = true
+			roots = append(roots, parent)
+		}
+		children[parent] = append(children[parent], key)
+	}
+
+	rows := []treeRow{}
+	var add func(key string, parent string, depth int)
+	add = func(key string, parent string, depth int) {
+		rows = append(rows, treeRow{
+			match:         byKey[key],
+			depth:         depth,
+			parent:        parent,
+			hasChildren:   len(children[key]) > 0,
+			isPlaceholder: placeholders[key],
+		})
+		if collapsed[key] {
+			return
+		}
+		for _, child := range children[key] {
+			add(child, key, depth+1)
+		}
+	}
+	for _, root := range roots {
+		add(root, "", 0)
+	}
+	return rows
+}
+
+func (m model) selectedTreeRow() (treeRow, bool) {
+	cursor := m.list.Cursor()
+	if cursor < 0 || cursor >= len(m.treeRows) {
+		return treeRow{}, false
+	}
+	return m.treeRows[cursor], true
+}
+
+func toggleTree(m model) (model, tea.Cmd) {
+	m.treeMode = !m.treeMode
+	m.rebuildRows()
+	return m.schedulePreview()
+}
+
+// setCollapsed collapses or expands a row, copying the map since models are
+// passed by value.
+func (m *model) setCollapsed(key string, collapsed bool) {
+	next := map[string]bool{}
+	for k := range m.collapsed {
+		next[k] = true
+	}
+	if collapsed {
+		next[key] = true
+	} else {
+		delete(next, key)
+	}
+	m.collapsed = next
+	m.rebuildRows()
+}
+
+// expandRow shows the children of the highlighted row, or moves to the first
+// one when they are shown already.
+func expandRow(m model) (model, tea.Cmd) {
+	row, ok := m.selectedTreeRow()
+	if !m.treeMode || !ok || !row.hasChildren {
+		return m, nil
+	}
+	if m.collapsed[row.match.ticket.Key] {
+		m.setCollapsed(row.match.ticket.Key, false)
+		return m, nil
+	}
+	m.list.MoveDown(1)
+	return m.schedulePreview()
+}
+
+// collapseRow hides the children of the highlighted row, or moves to its
+// parent when there is nothing to hide.
+func collapseRow(m model) (model, tea.Cmd) {
+	row, ok := m.selectedTreeRow()
+	if !m.treeMode || !ok {
+		return m, nil
+	}
+	if row.hasChildren && !m.collapsed[row.match.ticket.Key] {
+		m.setCollapsed(row.match.ticket.Key, true)
+		return m, nil
+	}
+	if row.parent != "" {
+		m.selectTicket(row.parent)
+	}
+	return m.schedulePreview()
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshwrn/jira-branch/internal/gui"
+	"github.com/mattn/go-runewidth"
+)
+
+func viewBatch(m model) string {
+	if m.batchResults == nil {
+		return lipgloss.NewStyle().
+			Width(m.width).
+			PaddingTop(2).
+			PaddingLeft(2).
+			Render(m.batchForm.View())
+	}
+
+	b := strings.Builder{}
+	bw := b.WriteString
+
+	done := 0
+	failed := 0
+	for _, result := range m.batchResults {
+		if result.status >= batchDone {
+			done++
+		}
+		if result.status == batchFailed {
+			failed++
+		}
+	}
+
+	title := fmt.Sprintf("Done with %d of %d tickets", done, len(m.batchResults))
+	if failed > 0 {
+		title += fmt.Sprintf(", %d failed", failed)
+	}
+	bw(lipgloss.NewStyle().
+		Foreground(gui.CurrentTheme.Primary).
+		PaddingLeft(1).
+		Render(title))
+	bw("\n")
+
+	lines := []string{}
+	for _, result := range m.batchResults {
+		lines = append(lines, viewBatchResult(m, result))
+	}
+	bw(lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(gui.CurrentTheme.Border).
+		Width(m.width-2).
+		Height(m.height-4).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n")))
+	bw("\n")
+
+	if !m.isRunningBatch {
+		bw(gui.CreateHelpItems(helpItems(m.keys.Back)))
+	}
+	return b.String()
+}
+
+func viewBatchResult(m model, result batchResult) string {
+	icon := gui.FaintWhiteText.Render("·")
+	detail := ""
+	switch result.status {
+	case batchRunning:
+		icon = m.spinner.View()
+	case batchDone:
+		icon = lipgloss.NewStyle().Foreground(gui.CurrentTheme.Success).Render("✓")
+		detail = gui.FaintWhiteText.Render(result.detail)
+	case batchQueued:
+		icon = lipgloss.NewStyle().Foreground(gui.CurrentTheme.Warning).Render("⏸")
+		detail = lipgloss.NewStyle().Foreground(gui.CurrentTheme.Warning).Render("queued, " + result.detail)
+	case batchFailed:
+		icon = gui.ErrorText.Render("✗")
+		detail = gui.ErrorText.Render(strings.ReplaceAll(result.err.Error(), "\n", " "))
+	}
+
+	key := lipgloss.NewStyle().Width(12).Render(result.ticket.Key)
+	summary := runewidth.Truncate(result.ticket.Summary, max(10, m.width/3), "…")
+	line := fmt.Sprintf("%s %s %s  %s", icon, key, summary, detail)
+	return lipgloss.NewStyle().MaxWidth(m.width - 4).Render(line)
+}
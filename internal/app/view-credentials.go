@@ -15,7 +15,7 @@ func CreateBaseCredentialInput(width int) textinput.Model {
 	ti.CharLimit = 200
 	ti.Width = width
 	ti.PlaceholderStyle = gui.FaintWhiteText
-	ti.TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("5"))
+	ti.TextStyle = lipgloss.NewStyle().Foreground(gui.CurrentTheme.Accent)
 	return ti
 }
 
@@ -58,12 +58,12 @@ func viewCredentials(m model) string {
 
 	b.WriteString(lipgloss.
 		NewStyle().
-		Foreground(lipgloss.Color("7")).
+		Foreground(gui.CurrentTheme.Text).
 		Render("Generate an API token at: "))
 	b.WriteString(
 		lipgloss.
 			NewStyle().
-			Foreground(lipgloss.Color("4")).
+			Foreground(gui.CurrentTheme.Primary).
 			Underline(true).
 			Render("https://id.atlassian.com/manage-profile/security/api-tokens"))
 
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshwrn/jira-branch/internal/gui"
+	"github.com/joshwrn/jira-branch/internal/jira"
+)
+
+type errorGuidance struct {
+	title     string
+	hints     []string
+	canSignIn bool
+}
+
+func createErrorGuidance(m model) errorGuidance {
+	var apiErr *jira.APIError
+	if !errors.As(m.err, &apiErr) {
+		return errorGuidance{title: m.err.Error()}
+	}
+
+	guidance := errorGuidance{}
+
+	switch apiErr.Kind() {
+	case jira.ErrorKindUnauthorized:
+		guidance.title = "Jira rejected your API token"
+		guidance.hints = []string{
+			"The token may have expired or been revoked.",
+			"Create a new one and sign in again.",
+		}
+		guidance.canSignIn = true
+	case jira.ErrorKindForbidden:
+		guidance.title = "You don't have permission to view these issues"
+		guidance.hints = []string{
+			"Ask a Jira admin for the Browse Projects permission.",
+		}
+		if m.config.ProjectKey != "" {
+			guidance.hints = append(guidance.hints, fmt.Sprintf(
+				"Check that %q in jira-branch.config.json is a project you can access.",
+				m.config.ProjectKey,
+			))
+		}
+	case jira.ErrorKindInvalidJQL:
+		guidance.title = "Jira couldn't understand the search query"
+		if clause := apiErr.JQLClause(); clause != "" {
+			guidance.hints = append(guidance.hints, fmt.Sprintf("Problem near: %s", clause))
+		}
+		guidance.hints = append(guidance.hints, apiErr.Details()...)
+	case jira.ErrorKindSiteNotFound:
+		guidance.title = fmt.Sprintf("No Jira site found at %s", m.credentials.JiraURL)
+		guidance.hints = []string{
+			"Check the Atlassian URL, it usually looks like your-company.atlassian.net.",
+		}
+		guidance.canSignIn = true
+	case jira.ErrorKindRateLimited:
+		guidance.title = "Jira is rate limiting requests"
+	case jira.ErrorKindServer:
+		guidance.title = "Jira is having problems"
+	case jira.ErrorKindNetwork:
+		guidance.title = "Couldn't connect to Jira"
+		guidance.hints = []string{
+			"Check your network connection.",
+		}
+	default:
+		guidance.title = apiErr.Error()
+	}
+
+	if apiErr.Retryable {
+		guidance.hints = append(guidance.hints, "This is usually temporary, try again in a moment.")
+	}
+
+	return guidance
+}
+
+func viewError(m model) string {
+	guidance := createErrorGuidance(m)
+
+	b := strings.Builder{}
+	bw := b.WriteString
+	bw(gui.ErrorText.Render(fmt.Sprintf("❌ %s", guidance.title)))
+	bw("\n")
+
+	for _, hint := range guidance.hints {
+		bw("\n")
+		bw(lipgloss.NewStyle().PaddingLeft(3).Render(gui.FaintWhiteText.Render(hint)))
+	}
+
+	var apiErr *jira.APIError
+	if errors.As(m.err, &apiErr) && apiErr.StatusCode != 0 {
+		bw("\n\n")
+		bw(lipgloss.NewStyle().PaddingLeft(3).Render(gui.FaintWhiteText.Render(
+			fmt.Sprintf("%d %s %s", apiErr.StatusCode, apiErr.Method, apiErr.Endpoint),
+		)))
+	}
+
+	helpItems := []gui.HelpItem{}
+	if errors.As(m.err, &apiErr) && apiErr.Retryable && m.isLoggedIn {
+		helpItems = append(helpItems, helpAs(m.keys.Refresh, "Retry"))
+	}
+	if guidance.canSignIn {
+		helpItems = append(helpItems, helpAs(m.keys.SignOut, "Sign in again"))
+	}
+	helpItems = append(helpItems, m.keys.quitHelp())
+
+	bw("\n\n")
+	bw(gui.CreateHelpItems(helpItems))
+	return b.String()
+}
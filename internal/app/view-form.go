@@ -1,53 +1,77 @@
 package app
 
 import (
-	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/joshwrn/jira-branch/internal/git_utils"
 	"github.com/joshwrn/jira-branch/internal/gui"
+	"github.com/joshwrn/jira-branch/internal/jira"
+	"github.com/joshwrn/jira-branch/internal/utils"
 )
 
 var sidebarWidth = 34
 
+// formPadding is the space above and left of the form.
+const formPadding = 2
+
 func viewForm(m model) string {
 	if m.isSubmittingForm {
 		return gui.CreateLoadingView(&gui.LoadingView{
 			Text:    "Creating branch and updating Jira...",
+			Detail:  m.submitProgress,
 			Width:   m.width,
 			Height:  m.height,
 			Spinner: m.spinner,
 		})
 	}
 
+	content := m.form.View() + "\n\n" + createFormFooter(m)
+
 	nameLen := len(*m.formBranchName)
 	formWidth := m.width - sidebarWidth - 5
 	if nameLen > formWidth-8 {
 		return lipgloss.NewStyle().
 			Width(m.width).
-			PaddingTop(2).
-			PaddingLeft(2).
-			Render(m.form.View())
+			PaddingTop(formPadding).
+			PaddingLeft(formPadding).
+			Render(content)
 	}
 
 	formView := lipgloss.NewStyle().
 		Width(formWidth).
-		PaddingTop(2).
-		PaddingLeft(2).
-		Render(m.form.View())
+		PaddingTop(formPadding).
+		PaddingLeft(formPadding).
+		Render(content)
 
 	sidebar := createSidebar(&m)
 
 	return lipgloss.JoinHorizontal(lipgloss.Top, formView, sidebar)
 }
 
+func formFooterItems(m model) []gui.HelpItem {
+	items := []gui.HelpItem{}
+	for _, action := range formActions(m) {
+		items = append(items, helpItem(action.binding))
+	}
+	return items
+}
+
+func createFormFooter(m model) string {
+	footer := gui.CreateHelpItems(formFooterItems(m))
+	if m.toast.text != "" {
+		footer += "\n" + viewToast(m.toast)
+	}
+	return footer
+}
+
 func createForm(m *model, initialBranchName string) *huh.Form {
 	branchName := initialBranchName
 
 	shouldMarkAsInProgress := true
-	status := m.list.SelectedRow()[3]
+	status := m.formTicket.Status
 	isInProgress := strings.EqualFold(status, "In Progress")
 
 	if isInProgress {
@@ -57,20 +81,29 @@ func createForm(m *model, initialBranchName string) *huh.Form {
 	m.formBranchName = &branchName
 	m.formShouldMarkAsInProgress = &shouldMarkAsInProgress
 
+	config := m.config
 	inputField := huh.NewInput().
 		Title("Branch name").
-		Value(m.formBranchName).Validate(func(value string) error {
-		if len(value) == 0 {
-			return errors.New("branch name is required")
-		}
-		if git_utils.BranchNameRegex.MatchString(value) {
-			return errors.New("branch name can only contain letters, numbers, '-', '_', '/' and '.'")
-		}
-		return nil
-	})
+		Value(m.formBranchName).
+		DescriptionFunc(func() string {
+			return branchNameHints(config, branchName)
+		}, m.formBranchName).
+		Validate(git_utils.ValidateBranchName)
+	m.formInput = inputField
 
 	fields := []huh.Field{inputField}
 
+	m.formSuggestion = nil
+	m.appliedSuggestion = initialBranchName
+	if suggestions := git_utils.BranchNameSuggestions(m.formTicket, m.branchNaming()); len(suggestions) > 1 {
+		suggestion := initialBranchName
+		m.formSuggestion = &suggestion
+		fields = append(fields, huh.NewSelect[string]().
+			Title("Suggestions").
+			Options(huh.NewOptions(suggestions...)...).
+			Value(m.formSuggestion))
+	}
+
 	if !isInProgress {
 		confirmField := huh.NewConfirm().
 			Title("Mark as in progress?").
@@ -80,6 +113,38 @@ func createForm(m *model, initialBranchName string) *huh.Form {
 		fields = append(fields, confirmField)
 	}
 
+	shouldPush := m.config.PushBranch
+	m.formShouldPush = &shouldPush
+	fields = append(fields, huh.NewConfirm().
+		Title(fmt.Sprintf("Push to %s?", m.config.GetRemote())).
+		Description("New branches are pushed and track the remote branch").
+		Value(m.formShouldPush).
+		Affirmative("Yes").
+		Negative("No"))
+
+	shouldComment := m.config.CommentOnBranch
+	m.formShouldComment = &shouldComment
+	repo := m.currentRepoName()
+	ticket := m.formTicket
+	fields = append(fields, huh.NewConfirm().
+		Title("Comment on the issue?").
+		DescriptionFunc(func() string {
+			return gui.FaintWhiteText.Render(branchComment(config, ticket, repo, branchName))
+		}, m.formBranchName).
+		Value(m.formShouldComment).
+		Affirmative("Yes").
+		Negative("No"))
+
+	// the model as it is once the form is set up, for the preview
+	preview := *m
+	fields = append(fields, huh.NewNote().
+		Title("This will").
+		DescriptionFunc(func() string {
+			return viewFormSteps(formSteps(preview))
+		}, []any{m.formBranchName, m.formShouldMarkAsInProgress, m.formShouldPush, m.formShouldComment}).
+		Next(true).
+		NextLabel("Confirm"))
+
 	form := huh.NewForm(
 		huh.NewGroup(fields...).WithTheme(customTheme()),
 	)
@@ -87,47 +152,90 @@ func createForm(m *model, initialBranchName string) *huh.Form {
 	return form
 }
 
+// viewFormSteps lists what submitting the form will do.
+func viewFormSteps(steps []formStep) string {
+	lines := []string{}
+	for _, step := range steps {
+		lines = append(lines, "• "+step.description)
+	}
+	return gui.FaintWhiteText.Render(strings.Join(lines, "\n"))
+}
+
+// branchComment is the comment posted on the issue when its branch is
+// created.
+func branchComment(config utils.JiraBranchConfig, ticket jira.JiraTicketsMsg, repo string, branchName string) string {
+	return utils.ExpandTemplate(config.GetBranchCommentTemplate(), map[string]string{
+		"branch":  branchName,
+		"repo":    repo,
+		"key":     ticket.Key,
+		"summary": ticket.Summary,
+	})
+}
+
+// branchNameHints are shown under the branch name while typing. Only names
+// git would reject stop the form, the rest are warnings.
+func branchNameHints(config utils.JiraBranchConfig, name string) string {
+	if err := git_utils.ValidateBranchName(name); err != nil {
+		return gui.ErrorText.Render(err.Error())
+	}
+
+	hints := []string{}
+	if git_utils.BranchExists(name) {
+		hints = append(hints, "A local branch with this name exists, it will be checked out")
+	}
+	if remotes := git_utils.RemoteBranches(name); len(remotes) > 0 {
+		hints = append(hints, fmt.Sprintf("%s exists already", strings.Join(remotes, ", ")))
+	}
+	if limit := config.MaxBranchLength; limit > 0 && len(name) > limit {
+		hints = append(hints, fmt.Sprintf("%d characters long, the limit is %d", len(name), limit))
+	}
+
+	return lipgloss.NewStyle().
+		Foreground(gui.CurrentTheme.Warning).
+		Render(strings.Join(hints, "\n"))
+}
+
 func createSidebar(m *model) string {
 	b := strings.Builder{}
 
 	divider := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8")).
+		Foreground(gui.CurrentTheme.Border).
 		Render(strings.Repeat("─", sidebarWidth-6))
 
 	b.WriteString(lipgloss.NewStyle().
-		Foreground(lipgloss.Color("4")).
-		Render(m.list.SelectedRow()[2]))
+		Foreground(gui.CurrentTheme.Primary).
+		Render(m.formTicket.Summary))
 
 	b.WriteString("\n")
 	b.WriteString(divider)
 	b.WriteString("\n")
 
 	b.WriteString(lipgloss.NewStyle().
-		Foreground(lipgloss.Color("5")).
-		Render(m.list.SelectedRow()[1]))
+		Foreground(gui.CurrentTheme.Accent).
+		Render(m.formTicket.Type))
 
 	b.WriteString("\n")
 	b.WriteString(divider)
 	b.WriteString("\n")
 
 	b.WriteString(lipgloss.NewStyle().
-		Foreground(lipgloss.Color("2")).
-		Render(m.list.SelectedRow()[3]))
+		Foreground(gui.CurrentTheme.Success).
+		Render(m.formTicket.Status))
 
 	b.WriteString("\n")
 	b.WriteString(divider)
 	b.WriteString("\n")
 
 	b.WriteString(lipgloss.NewStyle().
-		Foreground(lipgloss.Color("7")).
-		Render(m.list.SelectedRow()[4]))
+		Foreground(gui.CurrentTheme.Text).
+		Render(utils.FormatRelativeTime(m.formTicket.Created)))
 
 	sidebar := lipgloss.NewStyle().
 		Width(sidebarWidth).
 		Height(m.height-3).
 		BorderStyle(lipgloss.RoundedBorder()).
 		Padding(1, 3).
-		BorderForeground(lipgloss.Color("8")).
+		BorderForeground(gui.CurrentTheme.Border).
 		Render(b.String())
 
 	return sidebar
@@ -138,50 +246,50 @@ var catppuccin = huh.ThemeCatppuccin()
 func customTheme() *huh.Theme {
 	// Focused field styling
 	focused := catppuccin.Focused
-	focused.Title = focused.Title.Foreground(lipgloss.Color("4"))
-	focused.Description = focused.Description.Foreground(lipgloss.Color("7"))
-	focused.TextInput.Prompt = focused.TextInput.Prompt.Foreground(lipgloss.Color("4"))
+	focused.Title = focused.Title.Foreground(gui.CurrentTheme.Primary)
+	focused.Description = focused.Description.Foreground(gui.CurrentTheme.Text)
+	focused.TextInput.Prompt = focused.TextInput.Prompt.Foreground(gui.CurrentTheme.Primary)
 	focused.TextInput.Placeholder = focused.TextInput.Placeholder.
-		Foreground(lipgloss.Color("7")).Faint(true)
+		Foreground(gui.CurrentTheme.Text).Faint(true)
 	focused.TextInput.Text = focused.TextInput.Text.
-		Foreground(lipgloss.Color("7"))
+		Foreground(gui.CurrentTheme.Text)
 	focused.TextInput.Cursor = focused.TextInput.Cursor.
-		Foreground(lipgloss.Color("5"))
+		Foreground(gui.CurrentTheme.Accent)
 	focused.TextInput.CursorText = focused.TextInput.CursorText.
-		Foreground(lipgloss.Color("5"))
+		Foreground(gui.CurrentTheme.Accent)
 
-	focused.FocusedButton = focused.FocusedButton.Background(lipgloss.Color("4"))
-	focused.BlurredButton = focused.BlurredButton.Background(lipgloss.Color("0"))
+	focused.FocusedButton = focused.FocusedButton.Background(gui.CurrentTheme.Primary)
+	focused.BlurredButton = focused.BlurredButton.Background(gui.CurrentTheme.SelectedBackground)
 
 	focused.Base = focused.Base.
-		BorderForeground(lipgloss.Color("7"))
+		BorderForeground(gui.CurrentTheme.Text)
 
 	// Blurred field styling
 	blurred := catppuccin.Blurred
-	blurred.Title = blurred.Title.Foreground(lipgloss.Color("4"))
-	blurred.Description = blurred.Description.Foreground(lipgloss.Color("7"))
-	blurred.TextInput.Prompt = blurred.TextInput.Prompt.Foreground(lipgloss.Color("4"))
+	blurred.Title = blurred.Title.Foreground(gui.CurrentTheme.Primary)
+	blurred.Description = blurred.Description.Foreground(gui.CurrentTheme.Text)
+	blurred.TextInput.Prompt = blurred.TextInput.Prompt.Foreground(gui.CurrentTheme.Primary)
 	blurred.TextInput.Placeholder = blurred.TextInput.Placeholder.
-		Foreground(lipgloss.Color("7")).Faint(true)
+		Foreground(gui.CurrentTheme.Text).Faint(true)
 	blurred.TextInput.Text = blurred.TextInput.Text.
-		Foreground(lipgloss.Color("7"))
+		Foreground(gui.CurrentTheme.Text)
 	blurred.TextInput.Cursor = blurred.TextInput.Cursor.
-		Foreground(lipgloss.Color("5"))
+		Foreground(gui.CurrentTheme.Accent)
 	blurred.TextInput.CursorText = blurred.TextInput.CursorText.
-		Foreground(lipgloss.Color("5"))
+		Foreground(gui.CurrentTheme.Accent)
 
-	blurred.FocusedButton = blurred.FocusedButton.Background(lipgloss.Color("4"))
-	blurred.BlurredButton = blurred.BlurredButton.Background(lipgloss.Color("0"))
+	blurred.FocusedButton = blurred.FocusedButton.Background(gui.CurrentTheme.Primary)
+	blurred.BlurredButton = blurred.BlurredButton.Background(gui.CurrentTheme.SelectedBackground)
 
 	blurred.Base = blurred.Base.
-		BorderForeground(lipgloss.Color("0"))
+		BorderForeground(gui.CurrentTheme.SelectedBackground)
 
 	// Help styling
 	help := catppuccin.Help
-	help.Ellipsis = help.Ellipsis.Foreground(lipgloss.Color("7")).Faint(true)
-	help.ShortKey = help.ShortKey.Foreground(lipgloss.Color("7"))
-	help.ShortDesc = help.ShortDesc.Foreground(lipgloss.Color("7")).Faint(true)
-	help.ShortSeparator = help.ShortSeparator.Foreground(lipgloss.Color("7")).Faint(true)
+	help.Ellipsis = help.Ellipsis.Foreground(gui.CurrentTheme.Text).Faint(true)
+	help.ShortKey = help.ShortKey.Foreground(gui.CurrentTheme.Text)
+	help.ShortDesc = help.ShortDesc.Foreground(gui.CurrentTheme.Text).Faint(true)
+	help.ShortSeparator = help.ShortSeparator.Foreground(gui.CurrentTheme.Text).Faint(true)
 
 	return &huh.Theme{
 		Form:           catppuccin.Form,
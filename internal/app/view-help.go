@@ -0,0 +1,50 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshwrn/jira-branch/internal/gui"
+)
+
+// viewHelp lists every key binding, grouped by where it works.
+func viewHelp(m model) string {
+	keyStyle := lipgloss.NewStyle().Foreground(gui.CurrentTheme.Text).Width(14)
+	titleStyle := lipgloss.NewStyle().Foreground(gui.CurrentTheme.Primary).MarginBottom(1)
+
+	sections := []string{}
+	for _, scope := range keyScopes {
+		b := strings.Builder{}
+		b.WriteString(titleStyle.Render(scope.title))
+		b.WriteString("\n")
+		for _, binding := range m.keys.bindings(scope) {
+			b.WriteString(keyStyle.Render(allKeys(binding)))
+			b.WriteString(gui.FaintWhiteText.Render(binding.Help().Desc))
+			b.WriteString("\n")
+		}
+		sections = append(sections, lipgloss.NewStyle().
+			Width(36).
+			MarginRight(2).
+			MarginBottom(1).
+			Render(b.String()))
+	}
+
+	// as many sections side by side as fit
+	perRow := max(1, (m.width-2)/38)
+	rows := []string{}
+	for i := 0; i < len(sections); i += perRow {
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, sections[i:min(i+perRow, len(sections))]...))
+	}
+
+	content := lipgloss.NewStyle().
+		Padding(1, 2).
+		Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height-1).
+		MaxHeight(m.height-1).
+		Render(content) + "\n" + gui.CreateHelpItems([]gui.HelpItem{
+		{Key: "any key", Desc: "Close"},
+	})
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshwrn/jira-branch/internal/gui"
+	"github.com/joshwrn/jira-branch/internal/jira"
+)
+
+const maxJQLErrorLines = 3
+
+func createJQLInput(width int) textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = ": "
+	ti.CharLimit = 1000
+	ti.Width = width - lipgloss.Width(ti.Prompt) - 1
+	ti.PlaceholderStyle = gui.FaintWhiteText
+	ti.Placeholder = `Search Jira, e.g. login timeout or project = PRJ AND status = "In Review"`
+	ti.TextStyle = lipgloss.NewStyle().Foreground(gui.CurrentTheme.Accent)
+	return ti
+}
+
+// jqlErrorLines explains why Jira rejected the last JQL search, pointing at
+// the clause it choked on when that can be found in the input.
+func jqlErrorLines(m model) []string {
+	if m.jqlErr == nil {
+		return nil
+	}
+
+	var apiErr *jira.APIError
+	if !errors.As(m.jqlErr, &apiErr) {
+		return []string{gui.ErrorText.Render(m.jqlErr.Error())}
+	}
+	if apiErr.Kind() == jira.ErrorKindNetwork {
+		return []string{gui.ErrorText.Render("Couldn't connect to Jira")}
+	}
+
+	lines := []string{}
+	if clause := apiErr.JQLClause(); clause != "" {
+		if index := strings.Index(m.jqlInput.Value(), clause); index >= 0 {
+			offset := lipgloss.Width(m.jqlInput.Prompt) + lipgloss.Width(m.jqlInput.Value()[:index])
+			lines = append(lines, strings.Repeat(" ", offset)+gui.ErrorText.Render("^ problem here"))
+		} else {
+			lines = append(lines, gui.ErrorText.Render(fmt.Sprintf("Problem near: %s", clause)))
+		}
+	}
+	for _, detail := range apiErr.Details() {
+		lines = append(lines, gui.FaintWhiteText.Render(detail))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, gui.ErrorText.Render(apiErr.Error()))
+	}
+
+	if len(lines) > maxJQLErrorLines {
+		lines = lines[:maxJQLErrorLines]
+	}
+	for i, line := range lines {
+		lines[i] = lipgloss.NewStyle().MaxWidth(m.width).Render(line)
+	}
+	return lines
+}
+
+func viewJQLSearch(m model) string {
+	b := strings.Builder{}
+	b.WriteString(m.jqlInput.View())
+	b.WriteString("\n")
+	for _, line := range jqlErrorLines(m) {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
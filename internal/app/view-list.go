@@ -2,21 +2,35 @@ package app
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/joshwrn/jira-branch/internal/gui"
+	"github.com/joshwrn/jira-branch/internal/utils"
+	"github.com/mattn/go-runewidth"
 )
 
 func viewList(m model) string {
-	helper := gui.CreateHelpItems([]gui.HelpItem{
-		{Key: "j/k", Desc: "↓/↑"},
-		{Key: "enter", Desc: "Select ticket"},
-		{Key: "/", Desc: "Search"},
-		{Key: "r", Desc: "Refresh"},
-		{Key: "S", Desc: "Sign out"},
-		{Key: "q/ctrl+c", Desc: "Quit"},
-	})
+	if m.tab == "recent" {
+		return viewRecent(m)
+	}
+
+	k := m.keys
+	helper := gui.CreateHelpItems(slices.Concat(
+		[]gui.HelpItem{k.navigationHelp()},
+		helpItems(k.Select, k.ToggleSelect, k.Search, k.JQL, k.Preview, k.Refresh),
+		[]gui.HelpItem{helpAs(k.SwitchTab, "Recent"), helpAs(k.Help, "More"), k.quitHelp()},
+	))
+
+	if m.hasSelection() {
+		helper = gui.CreateHelpItems([]gui.HelpItem{
+			helpAs(k.ToggleSelect, "Toggle"),
+			helpAs(k.Select, fmt.Sprintf("Batch actions (%d)", len(m.selected))),
+			helpAs(k.Back, "Clear selection"),
+			k.quitHelp(),
+		})
+	}
 
 	searchView := strings.Builder{}
 	bw := searchView.WriteString
@@ -24,28 +38,97 @@ func viewList(m model) string {
 	if m.showSearch {
 		bw(m.searchInput.View())
 		bw("\n")
+		if m.searchErr != nil {
+			bw(viewSearchError(m))
+			bw("\n")
+		}
 		helper = gui.CreateHelpItems([]gui.HelpItem{
-			{Key: "esc", Desc: "Clear"},
-			{Key: "enter", Desc: "Confirm"},
+			helpAs(k.Back, "Clear"),
+			helpAs(k.Confirm, "Confirm"),
 		})
 	}
 
+	if m.showJQLSearch {
+		bw(viewJQLSearch(m))
+		helper = gui.CreateHelpItems([]gui.HelpItem{
+			helpAs(k.Back, "Cancel"),
+			helpAs(k.Confirm, "Search"),
+			{Key: k.HistoryUp.Help().Key + "/" + k.HistoryDown.Help().Key, Desc: "History"},
+		})
+	}
+
+	status := []string{}
+	if m.toast.text != "" {
+		status = append(status, viewToast(m.toast))
+	}
+	if m.pendingJQL != "" {
+		status = append(status, m.spinner.View()+gui.FaintWhiteText.Render(" searching"))
+	}
+	if m.jql != "" && !m.showJQLSearch {
+		status = append(status, lipgloss.NewStyle().
+			Foreground(gui.CurrentTheme.Warning).
+			Render(runewidth.Truncate(":"+m.jql, max(10, m.width/3), "…")))
+	}
 	if m.search != "" && !m.showSearch {
+		status = append(status, lipgloss.NewStyle().
+			Foreground(gui.CurrentTheme.Warning).
+			Render(fmt.Sprintf("/%s", m.search)))
+	}
+	if syncStatus := createSyncStatus(m); syncStatus != "" {
+		status = append(status, syncStatus)
+	}
+
+	if len(status) > 0 {
 		helperWidth := lipgloss.Width(helper)
 		availableWidth := m.width - helperWidth
-		searchText := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("3")).
-			Render(fmt.Sprintf("/%s", m.search))
-
 		helper = helper +
 			lipgloss.NewStyle().
 				Width(availableWidth-1).
 				Align(lipgloss.Right).
-				Render(searchText)
+				Render(strings.Join(status, gui.FaintWhiteText.Render(" • ")))
 	}
 
-	return searchView.String() + lipgloss.NewStyle().
+	content := lipgloss.NewStyle().
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("8")).
-		Render(m.list.View()) + "\n" + helper
+		BorderForeground(gui.CurrentTheme.Border).
+		Render(m.list.View())
+	if width := m.previewWidth(); width > 0 {
+		content = lipgloss.JoinHorizontal(lipgloss.Top, content, viewPreview(m, width, lipgloss.Height(content)))
+	}
+
+	return viewTabs(m) + "\n" + searchView.String() + content + "\n" + helper
+}
+
+func viewToast(toast toastMsg) string {
+	if toast.isError {
+		return gui.ErrorText.Render(toast.text)
+	}
+	return lipgloss.NewStyle().Foreground(gui.CurrentTheme.Success).Render(toast.text)
+}
+
+func createSyncStatus(m model) string {
+	parts := []string{}
+
+	if m.isOffline {
+		parts = append(parts, gui.ErrorText.Render("offline"))
+	}
+	if m.isRefreshing && !m.isLoading {
+		parts = append(parts, m.spinner.View()+gui.FaintWhiteText.Render(" refreshing"))
+	}
+	if !m.lastUpdated.IsZero() {
+		label := "updated"
+		if m.isStale || m.isOffline {
+			label = "cached"
+		}
+		parts = append(parts, gui.FaintWhiteText.Render(
+			fmt.Sprintf("%s %s", label, utils.FormatTimeAgo(m.lastUpdated)),
+		))
+	}
+	if m.queuedOperations > 0 {
+		parts = append(parts, lipgloss.NewStyle().
+			Foreground(gui.CurrentTheme.Warning).
+			Render(fmt.Sprintf("%d queued", m.queuedOperations)))
+	}
+
+	return strings.Join(parts, gui.FaintWhiteText.Render(" • "))
 }
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshwrn/jira-branch/internal/gui"
+)
+
+func viewOutbox(m model) string {
+	b := strings.Builder{}
+	bw := b.WriteString
+
+	bw(lipgloss.NewStyle().
+		Foreground(gui.CurrentTheme.Primary).
+		PaddingLeft(1).
+		Render(fmt.Sprintf("Pending Jira operations (%d)", len(m.outboxOperations))))
+	bw("\n")
+
+	if len(m.outboxOperations) == 0 {
+		bw(lipgloss.NewStyle().
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(gui.CurrentTheme.Border).
+			Width(m.width-2).
+			Height(m.height-4).
+			Padding(1, 2).
+			Render(gui.FaintWhiteText.Render("Nothing queued, everything has been sent to Jira.")))
+	} else {
+		bw(lipgloss.NewStyle().
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(gui.CurrentTheme.Border).
+			Render(m.outboxTable.View()))
+	}
+	bw("\n")
+
+	helper := gui.CreateHelpItems(helpItems(m.keys.Retry, m.keys.RetryAll, m.keys.Drop, m.keys.Back))
+
+	if m.outboxStatus != "" || m.outboxErr != nil {
+		helperWidth := lipgloss.Width(helper)
+		status := gui.FaintWhiteText.Render(m.outboxStatus)
+		if m.outboxErr != nil {
+			status = gui.ErrorText.Render(m.outboxErr.Error())
+		}
+		helper = helper + lipgloss.NewStyle().
+			Width(m.width-helperWidth-1).
+			Align(lipgloss.Right).
+			Render(status)
+	}
+
+	bw(helper)
+	return b.String()
+}
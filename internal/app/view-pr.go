@@ -0,0 +1,49 @@
+package app
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshwrn/jira-branch/internal/gui"
+)
+
+func viewPullRequest(m model) string {
+	if m.isPreparingPR || m.isCreatingPR {
+		text := "Getting the ticket..."
+		if m.isCreatingPR {
+			text = "Creating pull request..."
+		}
+		return gui.CreateLoadingView(&gui.LoadingView{
+			Text:    text,
+			Width:   m.width,
+			Height:  m.height,
+			Spinner: m.spinner,
+		})
+	}
+
+	k := m.keys
+	var content string
+	var help []gui.HelpItem
+	switch {
+	case m.prErr != nil:
+		content = gui.ErrorText.Render(m.prErr.Error())
+		help = helpItems(k.Back)
+	case m.prCreated.URL != "":
+		content = lipgloss.NewStyle().
+			Foreground(gui.CurrentTheme.Success).
+			Render("Created pull request") + "\n\n" + gui.Text.Render(m.prCreated.URL)
+		help = []gui.HelpItem{helpItem(k.Open), helpItem(k.CopyLink), helpItem(k.Back)}
+	default:
+		content = m.prForm.View()
+		help = []gui.HelpItem{helpAs(k.Back, "Cancel")}
+	}
+
+	footer := gui.CreateHelpItems(help)
+	if m.toast.text != "" {
+		footer += "\n" + viewToast(m.toast)
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.width - formPadding).
+		PaddingTop(formPadding).
+		PaddingLeft(formPadding).
+		Render(content + "\n\n" + footer)
+}
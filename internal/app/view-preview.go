@@ -0,0 +1,139 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshwrn/jira-branch/internal/gui"
+	"github.com/joshwrn/jira-branch/internal/jira"
+	"github.com/mattn/go-runewidth"
+)
+
+// viewPreview renders the details of the highlighted ticket next to the list,
+// with the border taking up width and height.
+func viewPreview(m model, width int, height int) string {
+	innerWidth := width - 4
+	innerHeight := height - 2
+	lines := []string{}
+
+	if ticket, ok := m.selectedTicket(); ok {
+		lines = previewLines(ticket, m.previews[ticket.Key], innerWidth)
+	}
+	if len(lines) > innerHeight {
+		lines = append(lines[:innerHeight-1], gui.FaintWhiteText.Render("…"))
+	}
+
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(gui.CurrentTheme.Border).
+		Padding(0, 1).
+		Width(width - 2).
+		Height(innerHeight).
+		Render(strings.Join(lines, "\n"))
+}
+
+func previewLines(ticket jira.JiraTicketsMsg, preview preview, width int) []string {
+	details := preview.details
+	// until the details are in, show what the list already knows
+	if details.Key == "" {
+		details = jira.IssueDetails{
+			Key:      ticket.Key,
+			Summary:  ticket.Summary,
+			Type:     ticket.Type,
+			Status:   ticket.Status,
+			Priority: ticket.Priority,
+			Assignee: ticket.Assignee,
+			Labels:   ticket.Labels,
+		}
+	}
+
+	lines := []string{
+		lipgloss.NewStyle().Foreground(gui.CurrentTheme.Primary).Bold(true).Render(details.Key) +
+			gui.FaintWhiteText.Render(" "+details.Type),
+	}
+	lines = append(lines, wrapLines(gui.Text.Bold(true), details.Summary, width)...)
+	lines = append(lines, "")
+
+	fields := []struct {
+		label string
+		value string
+	}{
+		{label: "Status", value: details.Status},
+		{label: "Priority", value: details.Priority},
+		{label: "Assignee", value: details.Assignee},
+		{label: "Reporter", value: details.Reporter},
+		{label: "Labels", value: strings.Join(details.Labels, ", ")},
+	}
+	for _, field := range fields {
+		if field.value == "" {
+			continue
+		}
+		label := gui.FaintWhiteText.Render(padLabel(field.label, 10))
+		lines = append(lines, label+gui.Text.Render(runewidth.Truncate(field.value, width-10, "…")))
+	}
+
+	switch {
+	case preview.isLoading:
+		lines = append(lines, "", gui.FaintWhiteText.Render("Loading details…"))
+		return lines
+	case preview.err != nil:
+		lines = append(lines, "", gui.ErrorText.Render("Couldn't load the details"))
+		return lines
+	case preview.details.Key == "":
+		return lines
+	}
+
+	if len(details.Links) > 0 {
+		lines = append(lines, "", gui.FaintWhiteText.Render("Links"))
+		for _, link := range details.Links {
+			title := link.Object.Title
+			if title == "" {
+				title = link.Object.URL
+			}
+			marker := "• "
+			if isPullRequestURL(link.Object.URL) {
+				marker = lipgloss.NewStyle().Foreground(gui.CurrentTheme.Success).Render("PR ")
+			}
+			lines = append(lines, marker+gui.Text.Render(runewidth.Truncate(title, width-3, "…")))
+		}
+	}
+
+	lines = append(lines, "")
+	if details.Description == "" {
+		lines = append(lines, gui.FaintWhiteText.Render("No description"))
+	} else {
+		lines = append(lines, wrapLines(gui.Text, collapseBlankLines(details.Description), width)...)
+	}
+
+	return lines
+}
+
+func padLabel(label string, width int) string {
+	return label + strings.Repeat(" ", max(1, width-runewidth.StringWidth(label)))
+}
+
+func wrapLines(style lipgloss.Style, text string, width int) []string {
+	return strings.Split(style.Width(width).Render(text), "\n")
+}
+
+// collapseBlankLines keeps at most one empty line between paragraphs so the
+// excerpt doesn't waste the little room it has.
+func collapseBlankLines(text string) string {
+	lines := []string{}
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" && len(lines) > 0 && lines[len(lines)-1] == "" {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isPullRequestURL(url string) bool {
+	for _, part := range []string{"/pull/", "/merge_requests/", "/pull-requests/"} {
+		if strings.Contains(url, part) {
+			return true
+		}
+	}
+	return false
+}
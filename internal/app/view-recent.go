@@ -0,0 +1,82 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshwrn/jira-branch/internal/gui"
+)
+
+var listTabs = []struct {
+	id    string
+	title string
+}{
+	{id: "tickets", title: "Tickets"},
+	{id: "recent", title: "Recent"},
+}
+
+// tabAt returns the tab at column x of the tab bar.
+func tabAt(x int) (string, bool) {
+	// the bar starts with a space, tabs are padded by one and a space apart
+	start := 1
+	for _, tab := range listTabs {
+		end := start + lipgloss.Width(tab.title) + 2
+		if x >= start && x < end {
+			return tab.id, true
+		}
+		start = end + 1
+	}
+	return "", false
+}
+
+func viewTabs(m model) string {
+	rendered := []string{}
+	for _, tab := range listTabs {
+		style := lipgloss.NewStyle().Padding(0, 1)
+		if tab.id == m.tab {
+			style = gui.SelectedStyle().Padding(0, 1)
+		} else {
+			style = style.Foreground(gui.CurrentTheme.Text).Faint(true)
+		}
+		rendered = append(rendered, style.Render(tab.title))
+	}
+	return " " + strings.Join(rendered, " ")
+}
+
+func viewRecent(m model) string {
+	k := m.keys
+	helper := gui.CreateHelpItems([]gui.HelpItem{
+		k.navigationHelp(),
+		helpAs(k.Select, "Check out"),
+		helpAs(k.SwitchTab, "Tickets"),
+		helpItem(k.Open),
+		helpAs(k.CopyBranch, "Copy branch"),
+		helpAs(k.DeleteBranch, "Delete"),
+		helpAs(k.Help, "More"),
+		k.quitHelp(),
+	})
+
+	if m.toast.text != "" {
+		helperWidth := lipgloss.Width(helper)
+		helper = helper + lipgloss.NewStyle().
+			Width(m.width-helperWidth-1).
+			Align(lipgloss.Right).
+			Render(viewToast(m.toast))
+	}
+
+	var content string
+	if len(m.recentEntries) == 0 {
+		content = lipgloss.NewStyle().
+			Width(m.width-2).
+			Height(m.height-4).
+			Padding(1, 2).
+			Render(gui.FaintWhiteText.Render("Branches you create or check out with jira-branch show up here."))
+	} else {
+		content = m.recentTable.View()
+	}
+
+	return viewTabs(m) + "\n" + lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(gui.CurrentTheme.Border).
+		Render(content) + "\n" + helper
+}
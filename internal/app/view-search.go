@@ -1,9 +1,13 @@
 package app
 
 import (
+	"errors"
+	"strings"
+
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/joshwrn/jira-branch/internal/gui"
+	"github.com/joshwrn/jira-branch/internal/query"
 )
 
 func createSearchInput(width int) textinput.Model {
@@ -11,7 +15,18 @@ func createSearchInput(width int) textinput.Model {
 	ti.CharLimit = 200
 	ti.Width = width
 	ti.PlaceholderStyle = gui.FaintWhiteText
-	ti.Placeholder = "Search for a ticket"
-	ti.TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("5"))
+	ti.Placeholder = `Search for a ticket, e.g. status:"in review" -type:bug created:<7d`
+	ti.TextStyle = lipgloss.NewStyle().Foreground(gui.CurrentTheme.Accent)
 	return ti
 }
+
+// viewSearchError points at the part of the search that couldn't be parsed.
+func viewSearchError(m model) string {
+	var parseErr *query.ParseError
+	if !errors.As(m.searchErr, &parseErr) {
+		return gui.ErrorText.Render(m.searchErr.Error())
+	}
+
+	offset := lipgloss.Width(m.searchInput.Prompt) + parseErr.Pos
+	return strings.Repeat(" ", offset) + gui.ErrorText.Render("^ "+parseErr.Message)
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshwrn/jira-branch/internal/gui"
+	"github.com/joshwrn/jira-branch/internal/timetrack"
+)
+
+func viewTime(m model) string {
+	if m.worklogForm != nil {
+		helper := gui.CreateHelpItems([]gui.HelpItem{helpAs(m.keys.Back, "Cancel")})
+		return lipgloss.NewStyle().
+			Width(m.width).
+			PaddingTop(2).
+			PaddingLeft(2).
+			Render(m.worklogForm.View() + "\n\n" + helper)
+	}
+
+	b := strings.Builder{}
+	bw := b.WriteString
+
+	var unlogged time.Duration
+	for _, total := range m.timeTotals {
+		unlogged += total.Duration
+	}
+	bw(lipgloss.NewStyle().
+		Foreground(gui.CurrentTheme.Primary).
+		PaddingLeft(1).
+		Render(fmt.Sprintf("Time not logged yet (%s)", timetrack.FormatDuration(unlogged))))
+	bw("\n")
+
+	if len(m.timeTotals) == 0 {
+		bw(lipgloss.NewStyle().
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(gui.CurrentTheme.Border).
+			Width(m.width-2).
+			Height(m.height-4).
+			Padding(1, 2).
+			Render(gui.FaintWhiteText.Render("Time spent on branches you check out with jira-branch shows up here.")))
+	} else {
+		bw(lipgloss.NewStyle().
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(gui.CurrentTheme.Border).
+			Render(m.timeTable.View()))
+	}
+	bw("\n")
+
+	k := m.keys
+	helper := gui.CreateHelpItems([]gui.HelpItem{
+		helpAs(k.Select, "Log work"),
+		helpAs(k.Drop, "Discard"),
+		helpItem(k.Refresh),
+		helpItem(k.Back),
+	})
+
+	if m.timeStatus != "" || m.timeErr != nil {
+		helperWidth := lipgloss.Width(helper)
+		status := gui.FaintWhiteText.Render(m.timeStatus)
+		if m.timeErr != nil {
+			status = gui.ErrorText.Render(m.timeErr.Error())
+		}
+		helper = helper + lipgloss.NewStyle().
+			Width(m.width-helperWidth-1).
+			Align(lipgloss.Right).
+			Render(status)
+	}
+
+	bw(helper)
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package automation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/joshwrn/jira-branch/internal/outbox"
+	"github.com/joshwrn/jira-branch/internal/utils"
+)
+
+// Event is something the tool did that rules can react to.
+type Event string
+
+const (
+	BranchCreated     Event = "branchCreated"
+	BranchPushed      Event = "branchPushed"
+	PullRequestOpened Event = "pullRequestOpened"
+	BranchDeleted     Event = "branchDeleted"
+)
+
+var events = []Event{BranchCreated, BranchPushed, PullRequestOpened, BranchDeleted}
+
+// Subject is what an event happened to. Its fields fill in the comments.
+type Subject struct {
+	IssueKey string
+	Summary  string
+	Branch   string
+	Repo     string
+	// the pull request, for PullRequestOpened
+	URL string
+}
+
+// Operations are the Jira operations the rules ask for when event happens,
+// in the order of the rules.
+func Operations(rules []utils.AutomationRule, event Event, subject Subject) []outbox.Operation {
+	operations := []outbox.Operation{}
+	for _, rule := range rules {
+		if Event(rule.On) != event {
+			continue
+		}
+		if rule.Transition != "" {
+			operations = append(operations, outbox.Operation{
+				Kind:       outbox.KindTransition,
+				IssueKey:   subject.IssueKey,
+				Transition: rule.Transition,
+			})
+		}
+		if rule.Comment != "" {
+			operations = append(operations, outbox.Operation{
+				Kind:     outbox.KindComment,
+				IssueKey: subject.IssueKey,
+				Comment: utils.ExpandTemplate(rule.Comment, map[string]string{
+					"key":     subject.IssueKey,
+					"summary": subject.Summary,
+					"branch":  subject.Branch,
+					"repo":    subject.Repo,
+					"url":     subject.URL,
+				}),
+			})
+		}
+	}
+	return operations
+}
+
+// Validate describes what is wrong with the rules, so it can be shown on
+// startup instead of rules silently never running.
+func Validate(rules []utils.AutomationRule) []string {
+	names := []string{}
+	for _, event := range events {
+		names = append(names, string(event))
+	}
+
+	problems := []string{}
+	for i, rule := range rules {
+		switch {
+		case !slices.Contains(events, Event(rule.On)):
+			problems = append(problems, fmt.Sprintf(
+				"rule %d runs on %q, try one of %s", i+1, rule.On, strings.Join(names, ", "),
+			))
+		case rule.Transition == "" && rule.Comment == "":
+			problems = append(problems, fmt.Sprintf("rule %d has no transition or comment", i+1))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"strings"
+	"time"
+
+	"github.com/joshwrn/jira-branch/internal/jira"
+	"github.com/joshwrn/jira-branch/internal/utils"
+)
+
+const cacheFileName = "tickets-cache.json"
+
+type Entry struct {
+	Tickets   []jira.JiraTicketsMsg `json:"tickets"`
+	FetchedAt time.Time             `json:"fetchedAt"`
+}
+
+type cacheFile struct {
+	Entries map[string]Entry `json:"entries"`
+}
+
+// Key identifies a ticket list by the Jira profile, the query that produced
+// it and the repository it was fetched from.
+func Key(credentials jira.Credentials, jql string) string {
+	repo, err := utils.GetGitRoot()
+	if err != nil {
+		repo = ""
+	}
+	return strings.Join([]string{credentials.JiraURL, credentials.Email, repo, jql}, "|")
+}
+
+func read() (cacheFile, error) {
+	file := cacheFile{Entries: map[string]Entry{}}
+	err := utils.ReadDataFile(cacheFileName, &file)
+	if file.Entries == nil {
+		file.Entries = map[string]Entry{}
+	}
+	return file, err
+}
+
+func Load(key string) (Entry, bool) {
+	file, err := read()
+	if err != nil {
+		utils.Log.Error().Err(err).Msg("Failed to read ticket cache")
+		return Entry{}, false
+	}
+	entry, ok := file.Entries[key]
+	return entry, ok
+}
+
+func Save(key string, tickets []jira.JiraTicketsMsg) error {
+	file, err := read()
+	if err != nil {
+		utils.Log.Error().Err(err).Msg("Failed to read ticket cache, starting a new one")
+		file = cacheFile{Entries: map[string]Entry{}}
+	}
+
+	file.Entries[key] = Entry{
+		Tickets:   tickets,
+		FetchedAt: time.Now(),
+	}
+
+	return utils.WriteDataFile(cacheFileName, file)
+}
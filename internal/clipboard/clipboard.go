@@ -0,0 +1,39 @@
+package clipboard
+
+import (
+	"os"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// Copy puts text on the clipboard. Over SSH the system clipboard belongs to
+// the wrong machine, so the text is handed to the local terminal with OSC 52
+// instead. That is also the fallback when no clipboard tool is installed.
+func Copy(text string) error {
+	if isRemote() {
+		return copyOSC52(text)
+	}
+	if err := clipboard.WriteAll(text); err != nil {
+		return copyOSC52(text)
+	}
+	return nil
+}
+
+func isRemote() bool {
+	return os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+}
+
+func copyOSC52(text string) error {
+	sequence := osc52.New(text)
+	switch {
+	case os.Getenv("TMUX") != "":
+		sequence = sequence.Tmux()
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		sequence = sequence.Screen()
+	}
+	// stdout belongs to the renderer
+	_, err := sequence.WriteTo(os.Stderr)
+	return err
+}
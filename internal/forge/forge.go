@@ -0,0 +1,170 @@
+package forge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joshwrn/jira-branch/internal/utils"
+)
+
+// PullRequest is what is needed to open a pull request, or a merge request
+// on GitLab.
+type PullRequest struct {
+	Title string
+	Body  string
+	// the branch with the changes and the one they should go into
+	Head  string
+	Base  string
+	Draft bool
+}
+
+// CreatedPullRequest is a pull request the forge accepted.
+type CreatedPullRequest struct {
+	Number int
+	URL    string
+}
+
+// Forge is the API of a code hosting service.
+type Forge interface {
+	CreatePullRequest(pr PullRequest) (CreatedPullRequest, error)
+}
+
+// New returns the API of the forge hosting remote. The API URL and the
+// environment variable holding the token can be changed in config, e.g. for
+// self-hosted forges.
+func New(remote Remote, config utils.ForgeConfig) (Forge, error) {
+	api := apiClient{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		baseURL:    strings.TrimSuffix(config.APIURL, "/"),
+	}
+
+	tokenEnv := config.TokenEnv
+	switch remote.Kind {
+	case GitHub:
+		if api.baseURL == "" {
+			api.baseURL = "https://api.github.com"
+			if remote.Host != "github.com" {
+				// GitHub Enterprise Server
+				api.baseURL = fmt.Sprintf("https://%s/api/v3", remote.Host)
+			}
+		}
+		if tokenEnv == "" {
+			tokenEnv = "GITHUB_TOKEN"
+		}
+		token := os.Getenv(tokenEnv)
+		if token == "" && config.TokenEnv == "" {
+			// the variable the gh CLI reads
+			token = os.Getenv("GH_TOKEN")
+		}
+		if token == "" {
+			return nil, fmt.Errorf("set %s to a GitHub token to create pull requests", tokenEnv)
+		}
+		api.headers = map[string]string{
+			"Authorization":        "Bearer " + token,
+			"Accept":               "application/vnd.github+json",
+			"X-GitHub-Api-Version": "2022-11-28",
+		}
+		return &gitHub{api: api, repo: remote.Repo}, nil
+
+	case GitLab:
+		if api.baseURL == "" {
+			api.baseURL = fmt.Sprintf("https://%s/api/v4", remote.Host)
+		}
+		if tokenEnv == "" {
+			tokenEnv = "GITLAB_TOKEN"
+		}
+		token := os.Getenv(tokenEnv)
+		if token == "" {
+			return nil, fmt.Errorf("set %s to a GitLab token to create merge requests", tokenEnv)
+		}
+		api.headers = map[string]string{"PRIVATE-TOKEN": token}
+		return &gitLab{api: api, repo: remote.Repo}, nil
+	}
+
+	return nil, fmt.Errorf("creating pull requests on %s isn't supported", remote.Host)
+}
+
+type apiClient struct {
+	httpClient *http.Client
+	baseURL    string
+	// auth and version headers of the forge
+	headers map[string]string
+}
+
+// post sends body as JSON and decodes the response into result.
+func (c apiClient) post(path string, body any, result any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", c.baseURL+path, bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	logEvent := utils.Log.Debug().
+		Str("url", req.URL.String()).
+		Dur("duration", time.Since(start))
+	if resp != nil {
+		logEvent = logEvent.Int("status_code", resp.StatusCode)
+	}
+	logEvent.Msg("Forge request")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return responseError(resp.StatusCode, responseBody)
+	}
+	return json.Unmarshal(responseBody, result)
+}
+
+// responseError picks the message out of an error response. Both GitHub and
+// GitLab put it in "message", GitLab sometimes as a list.
+func responseError(status int, body []byte) error {
+	var response struct {
+		Message json.RawMessage `json:"message"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	messages := []string{}
+	if json.Unmarshal(body, &response) == nil {
+		var message string
+		var list []string
+		switch {
+		case json.Unmarshal(response.Message, &message) == nil:
+			messages = append(messages, message)
+		case json.Unmarshal(response.Message, &list) == nil:
+			messages = append(messages, list...)
+		}
+		for _, e := range response.Errors {
+			if e.Message != "" {
+				messages = append(messages, e.Message)
+			}
+		}
+	}
+	if len(messages) == 0 {
+		messages = append(messages, strings.TrimSpace(string(body)))
+	}
+	return fmt.Errorf("%s (%d)", strings.Join(messages, ", "), status)
+}
@@ -0,0 +1,214 @@
+package forge
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/joshwrn/jira-branch/internal/utils"
+)
+
+type recordedRequest struct {
+	method string
+	path   string
+	header http.Header
+	body   map[string]any
+}
+
+// standIn is a local forge API that records the request and answers with
+// status and response.
+func standIn(t *testing.T, status int, response string) (*httptest.Server, *recordedRequest) {
+	t.Helper()
+	recorded := &recordedRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading request body: %v", err)
+		}
+		recorded.method = r.Method
+		recorded.path = r.URL.EscapedPath()
+		recorded.header = r.Header.Clone()
+		if err := json.Unmarshal(body, &recorded.body); err != nil {
+			t.Errorf("request body isn't JSON: %v", err)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server, recorded
+}
+
+func newForge(t *testing.T, remoteURL string, config utils.ForgeConfig) Forge {
+	t.Helper()
+	remote, err := ParseRemote(remoteURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api, err := New(remote, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return api
+}
+
+func TestGitHubCreatePullRequest(t *testing.T) {
+	server, recorded := standIn(t, http.StatusCreated, `{"number": 7, "html_url": "https://github.com/owner/repo/pull/7"}`)
+	t.Setenv("GITHUB_TOKEN", "github-token")
+
+	api := newForge(t, "git@github.com:owner/repo.git", utils.ForgeConfig{APIURL: server.URL})
+	created, err := api.CreatePullRequest(PullRequest{
+		Title: "PRJ-1: Fix login",
+		Body:  "body",
+		Head:  "feature/PRJ-1-fix_login",
+		Base:  "main",
+		Draft: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if created.Number != 7 || created.URL != "https://github.com/owner/repo/pull/7" {
+		t.Errorf("created = %+v", created)
+	}
+	if recorded.method != "POST" || recorded.path != "/repos/owner/repo/pulls" {
+		t.Errorf("request = %s %s", recorded.method, recorded.path)
+	}
+	if got := recorded.header.Get("Authorization"); got != "Bearer github-token" {
+		t.Errorf("Authorization = %q", got)
+	}
+	if got := recorded.header.Get("Accept"); got != "application/vnd.github+json" {
+		t.Errorf("Accept = %q", got)
+	}
+	want := map[string]any{
+		"title": "PRJ-1: Fix login",
+		"body":  "body",
+		"head":  "feature/PRJ-1-fix_login",
+		"base":  "main",
+		"draft": true,
+	}
+	for field, value := range want {
+		if recorded.body[field] != value {
+			t.Errorf("body %s = %v, want %v", field, recorded.body[field], value)
+		}
+	}
+}
+
+func TestGitLabCreateMergeRequest(t *testing.T) {
+	server, recorded := standIn(t, http.StatusCreated, `{"iid": 3, "web_url": "https://gitlab.example.com/group/sub/repo/-/merge_requests/3"}`)
+	t.Setenv("EXAMPLE_GITLAB_TOKEN", "gitlab-token")
+
+	api := newForge(t, "git@gitlab.example.com:group/sub/repo.git", utils.ForgeConfig{
+		APIURL:   server.URL,
+		TokenEnv: "EXAMPLE_GITLAB_TOKEN",
+	})
+	created, err := api.CreatePullRequest(PullRequest{
+		Title: "PRJ-1: Fix login",
+		Body:  "body",
+		Head:  "feature/PRJ-1-fix_login",
+		Base:  "main",
+		Draft: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if created.Number != 3 || created.URL != "https://gitlab.example.com/group/sub/repo/-/merge_requests/3" {
+		t.Errorf("created = %+v", created)
+	}
+	if recorded.method != "POST" || recorded.path != "/projects/group%2Fsub%2Frepo/merge_requests" {
+		t.Errorf("request = %s %s", recorded.method, recorded.path)
+	}
+	if got := recorded.header.Get("PRIVATE-TOKEN"); got != "gitlab-token" {
+		t.Errorf("PRIVATE-TOKEN = %q", got)
+	}
+	want := map[string]any{
+		"title":         "Draft: PRJ-1: Fix login",
+		"description":   "body",
+		"source_branch": "feature/PRJ-1-fix_login",
+		"target_branch": "main",
+	}
+	for field, value := range want {
+		if recorded.body[field] != value {
+			t.Errorf("body %s = %v, want %v", field, recorded.body[field], value)
+		}
+	}
+}
+
+func TestNewWithoutToken(t *testing.T) {
+	t.Setenv("GITLAB_TOKEN", "")
+	remote, err := ParseRemote("https://gitlab.com/group/repo.git", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(remote, utils.ForgeConfig{}); err == nil {
+		t.Error("expected an error without a token")
+	}
+}
+
+func TestResponseError(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "string message",
+			body: `{"message": "Not Found"}`,
+			want: "Not Found (422)",
+		},
+		{
+			name: "list message",
+			body: `{"message": ["Another open merge request already exists", "Branch is protected"]}`,
+			want: "Another open merge request already exists, Branch is protected (422)",
+		},
+		{
+			name: "field errors",
+			body: `{"message": "Validation Failed", "errors": [{"resource": "PullRequest", "code": "custom", "message": "A pull request already exists for owner:feature."}]}`,
+			want: "Validation Failed, A pull request already exists for owner:feature. (422)",
+		},
+		{
+			name: "not JSON",
+			body: "Bad Gateway",
+			want: "Bad Gateway (422)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := responseError(http.StatusUnprocessableEntity, []byte(test.body))
+			if err.Error() != test.want {
+				t.Errorf("responseError = %q, want %q", err.Error(), test.want)
+			}
+		})
+	}
+}
+
+func TestCreatePullRequestRejected(t *testing.T) {
+	server, _ := standIn(t, http.StatusUnprocessableEntity, `{"message": "Validation Failed", "errors": [{"message": "No commits between main and feature"}]}`)
+	t.Setenv("GITHUB_TOKEN", "github-token")
+
+	api := newForge(t, "https://github.com/owner/repo.git", utils.ForgeConfig{APIURL: server.URL})
+	_, err := api.CreatePullRequest(PullRequest{Title: "PRJ-1", Head: "feature", Base: "main"})
+	want := "GitHub didn't create the pull request: Validation Failed, No commits between main and feature (422)"
+	if err == nil || err.Error() != want {
+		t.Errorf("err = %v, want %q", err, want)
+	}
+}
+
+func TestLinksEscapeBranchNames(t *testing.T) {
+	remote, err := ParseRemote("git@github.com:owner/repo.git", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	branchURL := remote.BranchURL("feature/PRJ-1-fix_#1_100%")
+	if want := "https://github.com/owner/repo/tree/feature/PRJ-1-fix_%231_100%25"; branchURL != want {
+		t.Errorf("BranchURL = %q, want %q", branchURL, want)
+	}
+	compareURL := remote.CompareURL("release/2.0#rc", "feature/PRJ-1")
+	if want := "https://github.com/owner/repo/compare/release/2.0%23rc...feature/PRJ-1"; compareURL != want {
+		t.Errorf("CompareURL = %q, want %q", compareURL, want)
+	}
+}
@@ -0,0 +1,37 @@
+package forge
+
+import "fmt"
+
+type gitHub struct {
+	api  apiClient
+	repo string
+}
+
+type gitHubPullRequest struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Draft bool   `json:"draft"`
+}
+
+type gitHubCreated struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+}
+
+// https://docs.github.com/en/rest/pulls/pulls#create-a-pull-request
+func (g *gitHub) CreatePullRequest(pr PullRequest) (CreatedPullRequest, error) {
+	created := gitHubCreated{}
+	err := g.api.post(fmt.Sprintf("/repos/%s/pulls", g.repo), gitHubPullRequest{
+		Title: pr.Title,
+		Body:  pr.Body,
+		Head:  pr.Head,
+		Base:  pr.Base,
+		Draft: pr.Draft,
+	}, &created)
+	if err != nil {
+		return CreatedPullRequest{}, fmt.Errorf("GitHub didn't create the pull request: %w", err)
+	}
+	return CreatedPullRequest{Number: created.Number, URL: created.HTMLURL}, nil
+}
@@ -0,0 +1,44 @@
+package forge
+
+import (
+	"fmt"
+	"net/url"
+)
+
+type gitLab struct {
+	api  apiClient
+	repo string
+}
+
+type gitLabMergeRequest struct {
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	SourceBranch string `json:"source_branch"`
+	TargetBranch string `json:"target_branch"`
+}
+
+type gitLabCreated struct {
+	IID    int    `json:"iid"`
+	WebURL string `json:"web_url"`
+}
+
+// https://docs.gitlab.com/api/merge_requests/#create-mr
+func (g *gitLab) CreatePullRequest(pr PullRequest) (CreatedPullRequest, error) {
+	title := pr.Title
+	if pr.Draft {
+		// GitLab marks drafts by their title
+		title = "Draft: " + title
+	}
+
+	created := gitLabCreated{}
+	err := g.api.post(fmt.Sprintf("/projects/%s/merge_requests", url.PathEscape(g.repo)), gitLabMergeRequest{
+		Title:        title,
+		Description:  pr.Body,
+		SourceBranch: pr.Head,
+		TargetBranch: pr.Base,
+	}, &created)
+	if err != nil {
+		return CreatedPullRequest{}, fmt.Errorf("GitLab didn't create the merge request: %w", err)
+	}
+	return CreatedPullRequest{Number: created.IID, URL: created.WebURL}, nil
+}
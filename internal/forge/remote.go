@@ -0,0 +1,146 @@
+package forge
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/joshwrn/jira-branch/internal/utils"
+)
+
+type Kind string
+
+const (
+	GitHub    Kind = "github"
+	GitLab    Kind = "gitlab"
+	Bitbucket Kind = "bitbucket"
+)
+
+type urlTemplates struct {
+	branch  string
+	compare string
+}
+
+var builtinTemplates = map[Kind]urlTemplates{
+	GitHub: {
+		branch:  "https://{host}/{repo}/tree/{branch}",
+		compare: "https://{host}/{repo}/compare/{base}...{branch}",
+	},
+	GitLab: {
+		branch:  "https://{host}/{repo}/-/tree/{branch}",
+		compare: "https://{host}/{repo}/-/compare/{base}...{branch}",
+	},
+	Bitbucket: {
+		branch:  "https://{host}/{repo}/branch/{branch}",
+		compare: "https://{host}/{repo}/branches/compare/{branch}%0D{base}",
+	},
+}
+
+// Remote is a repository on a forge, as named by a git remote URL.
+type Remote struct {
+	Kind Kind
+	Host string
+	// owner/name, or the full group path on GitLab
+	Repo      string
+	templates urlTemplates
+}
+
+// ParseRemote reads a remote URL like git@github.com:owner/repo.git or
+// https://gitlab.example.com/group/repo.git. Self-hosted forges are picked up
+// from forges, keyed by host.
+func ParseRemote(remoteURL string, forges map[string]utils.ForgeConfig) (Remote, error) {
+	host, repo, err := splitRemoteURL(remoteURL)
+	if err != nil {
+		return Remote{}, err
+	}
+
+	remote := Remote{Host: host, Repo: repo}
+	config := forges[host]
+	switch {
+	case config.Type != "":
+		remote.Kind = Kind(strings.ToLower(config.Type))
+	case strings.Contains(host, "github"):
+		remote.Kind = GitHub
+	case strings.Contains(host, "gitlab"):
+		remote.Kind = GitLab
+	case strings.Contains(host, "bitbucket"):
+		remote.Kind = Bitbucket
+	}
+
+	remote.templates = builtinTemplates[remote.Kind]
+	if config.BranchURL != "" {
+		remote.templates.branch = config.BranchURL
+	}
+	if config.CompareURL != "" {
+		remote.templates.compare = config.CompareURL
+	}
+	if remote.templates.branch == "" || remote.templates.compare == "" {
+		return Remote{}, fmt.Errorf("don't know how to link to %s, add it to the forges in the config", host)
+	}
+	return remote, nil
+}
+
+func splitRemoteURL(remoteURL string) (string, string, error) {
+	remoteURL = strings.TrimSpace(remoteURL)
+	var host, path string
+
+	if !strings.Contains(remoteURL, "://") {
+		// scp-like syntax, [user@]host:path
+		hostPart, pathPart, ok := strings.Cut(remoteURL, ":")
+		if !ok {
+			return "", "", fmt.Errorf("unsupported remote URL %q", remoteURL)
+		}
+		if _, afterUser, found := strings.Cut(hostPart, "@"); found {
+			hostPart = afterUser
+		}
+		host, path = hostPart, pathPart
+	} else {
+		parsed, err := url.Parse(remoteURL)
+		if err != nil {
+			return "", "", fmt.Errorf("unsupported remote URL %q: %w", remoteURL, err)
+		}
+		host = parsed.Host
+		if parsed.Scheme != "http" && parsed.Scheme != "https" {
+			// the SSH port isn't the one of the web interface
+			host = parsed.Hostname()
+		}
+		path = parsed.Path
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || !strings.Contains(path, "/") {
+		return "", "", fmt.Errorf("unsupported remote URL %q", remoteURL)
+	}
+	return host, path, nil
+}
+
+// expand fills in a URL template, escaping the branch names so characters
+// like '#' or '%' don't end up as part of the URL's syntax.
+func (r Remote) expand(template string, values map[string]string) string {
+	for name, value := range values {
+		values[name] = escapePath(value)
+	}
+	values["host"] = r.Host
+	values["repo"] = r.Repo
+	return utils.ExpandTemplate(template, values)
+}
+
+// escapePath escapes each segment of a path, keeping the slashes between
+// them.
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// BranchURL is the page of a branch on the forge.
+func (r Remote) BranchURL(branchName string) string {
+	return r.expand(r.templates.branch, map[string]string{"branch": branchName})
+}
+
+// CompareURL is the page comparing a branch with base.
+func (r Remote) CompareURL(base string, branchName string) string {
+	return r.expand(r.templates.compare, map[string]string{"branch": branchName, "base": base})
+}
@@ -0,0 +1,155 @@
+package fuzzy
+
+import (
+	"math"
+	"unicode"
+)
+
+const (
+	scoreMatch        = 16
+	scoreGap          = 1
+	scoreLeadingGap   = 1
+	maxLeadingPenalty = 8
+	bonusConsecutive  = 8
+	bonusWordStart    = 12
+	bonusCaseMatch    = 1
+	noScore           = math.MinInt32
+)
+
+type Result struct {
+	Score int
+	// Rune indices of the matched characters in the text
+	Positions []int
+}
+
+// Match reports whether the characters of pattern appear in text in order,
+// ignoring case. The alignment with the best score is picked, favoring matches
+// at word starts and runs of consecutive characters.
+func Match(pattern, text string) (Result, bool) {
+	patternRunes := []rune(pattern)
+	textRunes := []rune(text)
+	n, m := len(patternRunes), len(textRunes)
+
+	if n == 0 {
+		return Result{}, true
+	}
+	if n > m || !isSubsequence(patternRunes, textRunes) {
+		return Result{}, false
+	}
+
+	bonuses := make([]int, m)
+	for j := range textRunes {
+		if isWordStart(textRunes, j) {
+			bonuses[j] = bonusWordStart
+		}
+	}
+
+	// score[i][j] is the best score for matching pattern[:i+1] with
+	// pattern[i] landing on text[j]
+	score := make([][]int, n)
+	parent := make([][]int, n)
+	for i := range n {
+		score[i] = make([]int, m)
+		parent[i] = make([]int, m)
+		for j := range m {
+			score[i][j] = noScore
+			parent[i][j] = -1
+		}
+	}
+
+	for i := range n {
+		// best score of pattern[:i] ending two or more characters before j,
+		// with the gap penalty already applied
+		gapBest, gapBestIndex := noScore, -1
+
+		for j := i; j < m; j++ {
+			if i > 0 && j >= 2 {
+				if gapBest != noScore {
+					gapBest -= scoreGap
+				}
+				if previous := score[i-1][j-2]; previous != noScore && previous-scoreGap > gapBest {
+					gapBest, gapBestIndex = previous-scoreGap, j-2
+				}
+			}
+
+			if !equalFold(patternRunes[i], textRunes[j]) {
+				continue
+			}
+
+			charScore := scoreMatch + bonuses[j]
+			if patternRunes[i] == textRunes[j] {
+				charScore += bonusCaseMatch
+			}
+
+			if i == 0 {
+				score[i][j] = charScore - min(j*scoreLeadingGap, maxLeadingPenalty)
+				continue
+			}
+
+			best, from := noScore, -1
+			if previous := score[i-1][j-1]; j >= 1 && previous != noScore {
+				best, from = previous+bonusConsecutive, j-1
+			}
+			if gapBest > best {
+				best, from = gapBest, gapBestIndex
+			}
+			if best == noScore {
+				continue
+			}
+
+			score[i][j] = best + charScore
+			parent[i][j] = from
+		}
+	}
+
+	end := -1
+	for j := n - 1; j < m; j++ {
+		if score[n-1][j] != noScore && (end == -1 || score[n-1][j] > score[n-1][end]) {
+			end = j
+		}
+	}
+	if end == -1 {
+		return Result{}, false
+	}
+
+	positions := make([]int, n)
+	for i, j := n-1, end; i >= 0; i-- {
+		positions[i] = j
+		j = parent[i][j]
+	}
+
+	return Result{Score: score[n-1][end], Positions: positions}, true
+}
+
+func isSubsequence(pattern, text []rune) bool {
+	i := 0
+	for _, r := range text {
+		if i < len(pattern) && equalFold(pattern[i], r) {
+			i++
+		}
+	}
+	return i == len(pattern)
+}
+
+func equalFold(a, b rune) bool {
+	return unicode.ToLower(a) == unicode.ToLower(b)
+}
+
+// isWordStart treats the first character, anything after a separator, camel
+// case humps and the switch between letters and digits as the start of a word.
+func isWordStart(text []rune, j int) bool {
+	if j == 0 {
+		return true
+	}
+
+	previous, current := text[j-1], text[j]
+	switch {
+	case !unicode.IsLetter(previous) && !unicode.IsDigit(previous):
+		return unicode.IsLetter(current) || unicode.IsDigit(current)
+	case unicode.IsLower(previous) && unicode.IsUpper(current):
+		return true
+	case unicode.IsLetter(previous) && unicode.IsDigit(current):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package git_utils
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/joshwrn/jira-branch/internal/jira"
+)
+
+// shortSlugWords is how much of the summary the short branch name keeps.
+const shortSlugWords = 4
+
+func branchPrefix(ticket jira.JiraTicketsMsg) string {
+	if ticket.Type == "Bug" {
+		return "bugfix/"
+	}
+	return "feature/"
+}
+
+func formatBranchName(prefix string, key string, summary string) string {
+	branchName := prefix + key
+	if summary != "" {
+		branchName += "-" + strings.ToLower(summary)
+	}
+	branchName = strings.ReplaceAll(branchName, " ", "_")
+	return sanitizeBranchName(BranchNameRegex.ReplaceAllString(branchName, ""))
+}
+
+var (
+	repeatedDots    = regexp.MustCompile(`\.{2,}`)
+	repeatedSlashes = regexp.MustCompile(`/{2,}`)
+)
+
+// sanitizeBranchName bends a name made of allowed characters to the rest of
+// the rules ValidateBranchName checks, e.g. for summaries ending in a dot.
+func sanitizeBranchName(name string) string {
+	name = repeatedDots.ReplaceAllString(name, ".")
+	name = repeatedSlashes.ReplaceAllString(name, "/")
+
+	parts := []string{}
+	for _, part := range strings.Split(name, "/") {
+		for {
+			trimmed := strings.TrimSuffix(strings.Trim(part, "."), ".lock")
+			if trimmed == part {
+				break
+			}
+			part = trimmed
+		}
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, "/")
+}
+
+// BranchNameSuggestions are the names FormatBranchName would pick with more
+// or less of the summary, starting with the one it does pick. Subtasks named
+// after their parent also get their own names.
+func BranchNameSuggestions(ticket jira.JiraTicketsMsg, naming BranchNaming) []string {
+	named := naming.namedTicket(ticket)
+	suggestions := slugSuggestions(named)
+	if named.Key != ticket.Key {
+		suggestions = append(suggestions, slugSuggestions(ticket)...)
+	}
+
+	unique := []string{}
+	for _, suggestion := range suggestions {
+		if !slices.Contains(unique, suggestion) {
+			unique = append(unique, suggestion)
+		}
+	}
+	return unique
+}
+
+func slugSuggestions(ticket jira.JiraTicketsMsg) []string {
+	prefix := branchPrefix(ticket)
+	words := strings.Fields(ticket.Summary)
+
+	suggestions := []string{formatBranchName(prefix, ticket.Key, ticket.Summary)}
+	if len(words) > shortSlugWords {
+		suggestions = append(suggestions, formatBranchName(prefix, ticket.Key, strings.Join(words[:shortSlugWords], " ")))
+	}
+	return append(suggestions, formatBranchName(prefix, ticket.Key, ""))
+}
+
+// ValidateBranchName applies the rules of git check-ref-format --branch, so
+// a bad name is caught in the form instead of by git.
+func ValidateBranchName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("branch name is required")
+	case name == "@", name == "HEAD":
+		return fmt.Errorf("branch name can't be %q", name)
+	case strings.HasPrefix(name, "-"):
+		return errors.New("branch name can't start with '-'")
+	case strings.HasPrefix(name, "/"), strings.HasSuffix(name, "/"):
+		return errors.New("branch name can't start or end with '/'")
+	case strings.HasSuffix(name, "."):
+		return errors.New("branch name can't end with '.'")
+	case strings.Contains(name, "//"):
+		return errors.New("branch name can't contain '//'")
+	case strings.Contains(name, ".."):
+		return errors.New("branch name can't contain '..'")
+	case strings.Contains(name, "@{"):
+		return errors.New("branch name can't contain '@{'")
+	}
+
+	for _, r := range name {
+		switch {
+		case r == ' ':
+			return errors.New("branch name can't contain spaces")
+		case r < 0x20 || r == 0x7f || unicode.IsControl(r):
+			return errors.New("branch name can't contain control characters")
+		case strings.ContainsRune(`~^:?*[\`, r):
+			return fmt.Errorf("branch name can't contain '%c'", r)
+		}
+	}
+
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") {
+			return errors.New("parts of a branch name can't start with '.'")
+		}
+		if strings.HasSuffix(part, ".lock") {
+			return errors.New("parts of a branch name can't end with '.lock'")
+		}
+	}
+
+	return nil
+}
+
+// RemoteBranches lists the remote tracking branches with the same name as a
+// local branch, such as origin/feature/PRJ-1.
+func RemoteBranches(branchName string) []string {
+	output, err := exec.Command(
+		"git", "for-each-ref", "--format=%(refname:short)", "refs/remotes/*/"+branchName,
+	).Output()
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(string(output))
+}
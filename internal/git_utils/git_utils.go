@@ -4,49 +4,167 @@ import (
 	"fmt"
 	"os/exec"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joshwrn/jira-branch/internal/jira"
-
-	tea "github.com/charmbracelet/bubbletea"
 )
 
 var BranchNameRegex = regexp.MustCompile(`[^a-zA-Z0-9-_./]`)
 
-type errMsg error
+// BranchNaming tweaks the names FormatBranchName picks.
+type BranchNaming struct {
+	// Subtasks are named after their parent, so they share its branch
+	ParentForSubtasks bool
+}
+
+func FormatBranchName(ticket jira.JiraTicketsMsg, naming BranchNaming) string {
+	ticket = naming.namedTicket(ticket)
+	return formatBranchName(branchPrefix(ticket), ticket.Key, ticket.Summary)
+}
+
+// namedTicket is the ticket a branch is named after.
+func (n BranchNaming) namedTicket(ticket jira.JiraTicketsMsg) jira.JiraTicketsMsg {
+	if !n.ParentForSubtasks || !ticket.IsSubtask || ticket.Parent == "" {
+		return ticket
+	}
+	return jira.JiraTicketsMsg{
+		Key:     ticket.Parent,
+		Summary: ticket.ParentSummary,
+		Type:    ticket.ParentType,
+	}
+}
+
+// CheckoutBranch switches to branchName in the current repository, creating
+// it first when it doesn't exist yet. It reports whether it was created.
+func CheckoutBranch(branchName string) (bool, error) {
+	branchExists := BranchExists(branchName)
+
+	var cmd *exec.Cmd
 
-func FormatBranchName(ticket jira.JiraTicketsMsg) string {
-	prefix := "feature/"
-	if ticket.Type == "Bug" {
-		prefix = "bugfix/"
+	if branchExists {
+		cmd = exec.Command("git", "checkout", branchName)
+	} else {
+		cmd = exec.Command("git", "checkout", "-b", branchName)
 	}
 
-	branchName := prefix + ticket.Key + "-" + strings.ToLower(ticket.Summary)
-	branchName = strings.ReplaceAll(branchName, " ", "_")
-	branchName = BranchNameRegex.ReplaceAllString(branchName, "")
+	output, err := cmd.CombinedOutput()
 
-	return branchName
+	if err != nil {
+		return false, fmt.Errorf("failed to checkout branch %s: %v\n\nOutput: %s", branchName, err, string(output))
+	}
+
+	return !branchExists, nil
+}
+
+// SwitchBranch checks out an existing branch in the repository at repo.
+func SwitchBranch(repo string, branchName string) error {
+	check := exec.Command("git", "-C", repo, "show-ref", "--verify", "--quiet", "refs/heads/"+branchName)
+	if check.Run() != nil {
+		return fmt.Errorf("branch %s no longer exists", branchName)
+	}
+
+	output, err := exec.Command("git", "-C", repo, "checkout", branchName).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to checkout branch %s: %v\n\nOutput: %s", branchName, err, strings.TrimSpace(string(output)))
+	}
+	return nil
 }
 
-func CheckoutBranch(branchName string) tea.Cmd {
-	return func() tea.Msg {
-		checkCmd := exec.Command("git", "show-ref", "--verify", "--quiet", "refs/heads/"+branchName)
-		branchExists := checkCmd.Run() == nil
+func BranchExists(branchName string) bool {
+	return exec.Command("git", "show-ref", "--verify", "--quiet", "refs/heads/"+branchName).Run() == nil
+}
 
-		var cmd *exec.Cmd
+// CreateBranch creates a branch from HEAD without checking it out.
+func CreateBranch(branchName string) error {
+	if BranchExists(branchName) {
+		return fmt.Errorf("branch %s already exists", branchName)
+	}
 
-		if branchExists {
-			cmd = exec.Command("git", "checkout", branchName)
-		} else {
-			cmd = exec.Command("git", "checkout", "-b", branchName)
-		}
+	output, err := exec.Command("git", "branch", branchName).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to create branch %s: %v\n\nOutput: %s", branchName, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// CurrentBranch is the branch checked out in the repository at repo.
+func CurrentBranch(repo string) (string, error) {
+	output, err := exec.Command("git", "-C", repo, "branch", "--show-current").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
 
-		output, err := cmd.CombinedOutput()
+// SwitchedAwayAt finds when branchName was last switched away from after
+// since, using the reflog of the repository at repo.
+func SwitchedAwayAt(repo string, branchName string, since time.Time) (time.Time, bool) {
+	output, err := exec.Command(
+		"git", "-C", repo, "reflog", "show", "--date=unix", "--format=%gd%x09%gs", "HEAD",
+	).Output()
+	if err != nil {
+		return time.Time{}, false
+	}
 
+	// newest first, so the last match is the first switch after since
+	switchedAt := time.Time{}
+	prefix := fmt.Sprintf("checkout: moving from %s to ", branchName)
+	for _, line := range strings.Split(string(output), "\n") {
+		selector, subject, ok := strings.Cut(line, "\t")
+		if !ok || !strings.HasPrefix(subject, prefix) || subject == prefix+branchName {
+			continue
+		}
+		// HEAD@{1700000000}
+		start, end := strings.Index(selector, "{"), strings.LastIndex(selector, "}")
+		if start == -1 || end < start {
+			continue
+		}
+		seconds, err := strconv.ParseInt(selector[start+1:end], 10, 64)
 		if err != nil {
-			return errMsg(fmt.Errorf("failed to checkout branch %s: %v\n\nOutput: %s", branchName, err, string(output)))
+			continue
 		}
+		at := time.Unix(seconds, 0)
+		if at.Before(since.Truncate(time.Second)) {
+			break
+		}
+		switchedAt = at
+	}
+
+	return switchedAt, !switchedAt.IsZero()
+}
+
+// RemoteURL is the fetch URL of a remote of the current repository.
+func RemoteURL(remote string) (string, error) {
+	output, err := exec.Command("git", "remote", "get-url", remote).Output()
+	if err != nil {
+		return "", fmt.Errorf("no remote named %s", remote)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// DefaultBranch is the branch the remote's HEAD points to, usually main.
+func DefaultBranch(remote string) string {
+	output, err := exec.Command("git", "symbolic-ref", "--short", "refs/remotes/"+remote+"/HEAD").Output()
+	if err != nil {
+		return "main"
+	}
+	return strings.TrimPrefix(strings.TrimSpace(string(output)), remote+"/")
+}
+
+// RemoteBranchExists reports whether the remote has branchName, as far as
+// the last fetch or push knows.
+func RemoteBranchExists(remote string, branchName string) bool {
+	return exec.Command("git", "show-ref", "--verify", "--quiet", "refs/remotes/"+remote+"/"+branchName).Run() == nil
+}
 
-		return tea.Quit()
+// DeleteBranch deletes a local branch in the repository at repo. Like
+// `git branch -d`, it refuses branches that aren't merged.
+func DeleteBranch(repo string, branchName string) error {
+	output, err := exec.Command("git", "-C", repo, "branch", "--delete", branchName).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to delete branch %s: %s", branchName, strings.TrimSpace(string(output)))
 	}
+	return nil
 }
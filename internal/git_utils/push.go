@@ -0,0 +1,75 @@
+package git_utils
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// PushBranch pushes branchName to remote and sets it as the upstream. The
+// lines git prints while pushing are passed to progress as they come in.
+//
+// git can't ask for credentials from inside the app, so it is told to fail
+// instead of prompting, and the error carries what git said.
+func PushBranch(remote string, branchName string, progress func(line string)) error {
+	cmd := exec.Command("git", "push", "--progress", "--set-upstream", remote, branchName)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if os.Getenv("GIT_SSH_COMMAND") == "" && gitConfig("core.sshCommand") == "" {
+		cmd.Env = append(cmd.Env, "GIT_SSH_COMMAND=ssh -o BatchMode=yes")
+	}
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to push branch %s: %w", branchName, err)
+	}
+
+	output := []string{}
+	scanner := bufio.NewScanner(stderr)
+	scanner.Split(scanProgressLines)
+	for scanner.Scan() {
+		// progress updates end with a carriage return instead of a newline,
+		// they are only shown while pushing and left out of the error
+		isUpdate := strings.HasSuffix(scanner.Text(), "\r")
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !isUpdate {
+			output = append(output, line)
+		}
+		if progress != nil {
+			progress(line)
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("failed to push branch %s to %s: %v\n\nOutput: %s", branchName, remote, err, strings.Join(output, "\n"))
+	}
+	return nil
+}
+
+// scanProgressLines splits at carriage returns as well as newlines, keeping
+// them at the end of the line.
+func scanProgressLines(data []byte, atEOF bool) (int, []byte, error) {
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i+1], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+func gitConfig(name string) string {
+	output, err := exec.Command("git", "config", "--get", name).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
@@ -15,9 +15,7 @@ func CreateHelpItems(items []HelpItem) string {
 	b := strings.Builder{}
 
 	for index, item := range items {
-		b.WriteString(lipgloss.NewStyle().
-			Foreground(lipgloss.Color("7")).
-			Render(item.Key))
+		b.WriteString(Text.Render(item.Key))
 		b.WriteString(" ")
 		b.WriteString(FaintWhiteText.Render(item.Desc))
 		if index != len(items)-1 {
@@ -29,3 +27,17 @@ func CreateHelpItems(items []HelpItem) string {
 		PaddingLeft(1).
 		Render(b.String())
 }
+
+// HelpItemAt returns the index of the item at column x of a help bar made by
+// CreateHelpItems.
+func HelpItemAt(items []HelpItem, x int) (int, bool) {
+	start := 1
+	for index, item := range items {
+		end := start + lipgloss.Width(item.Key) + 1 + lipgloss.Width(item.Desc)
+		if x >= start && x < end {
+			return index, true
+		}
+		start = end + lipgloss.Width(" • ")
+	}
+	return 0, false
+}
@@ -10,8 +10,10 @@ import (
 type LoadingView struct {
 	Spinner spinner.Model
 	Text    string
-	Width   int
-	Height  int
+	// shown faint under the text, e.g. the progress of a command
+	Detail string
+	Width  int
+	Height int
 }
 
 func CreateLoadingView(m *LoadingView) string {
@@ -21,6 +23,10 @@ func CreateLoadingView(m *LoadingView) string {
 	bw(" ")
 	bw(m.Text)
 	bw("\n\n")
+	if m.Detail != "" {
+		bw(FaintWhiteText.Render(m.Detail))
+		bw("\n\n")
+	}
 	b.WriteString(CreateHelpItems([]HelpItem{
 		{Key: "q/ctrl+c", Desc: "Quit"},
 	}))
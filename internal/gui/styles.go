@@ -4,10 +4,27 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-var FaintWhiteText = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("7")).
-	Faint(true)
+var (
+	FaintWhiteText lipgloss.Style
+	ErrorText      lipgloss.Style
+	Text           lipgloss.Style
+)
+
+func init() {
+	applyTheme()
+}
+
+// applyTheme rebuilds the shared styles from CurrentTheme.
+func applyTheme() {
+	theme := CurrentTheme
+
+	FaintWhiteText = lipgloss.NewStyle().
+		Foreground(theme.Text).
+		Faint(true)
+
+	ErrorText = lipgloss.NewStyle().
+		Foreground(theme.Error).
+		Bold(true)
 
-var ErrorText = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("9")).
-	Bold(true)
+	Text = lipgloss.NewStyle().Foreground(theme.Text)
+}
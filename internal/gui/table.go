@@ -0,0 +1,341 @@
+package gui
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+type Column struct {
+	Title string
+	Width int
+}
+
+type Cell struct {
+	Text string
+	// Rune indices of Text to render with the highlight style
+	Highlights []int
+	// Dims the cell, for rows that are only there for context
+	Faint bool
+}
+
+type Row []Cell
+
+type TableStyles struct {
+	Header    lipgloss.Style
+	Cell      lipgloss.Style
+	Selected  lipgloss.Style
+	Highlight lipgloss.Style
+}
+
+func DefaultTableStyles() TableStyles {
+	defaults := table.DefaultStyles()
+	return TableStyles{
+		Header:    defaults.Header,
+		Cell:      defaults.Cell,
+		Selected:  defaults.Selected,
+		Highlight: lipgloss.NewStyle().Bold(true).Underline(true),
+	}
+}
+
+// Table is a scrolling table like the one in bubbles, except that cells are
+// plain text with optional highlighted characters. Styling happens while
+// rendering, so highlights never get cut in half by truncation.
+type Table struct {
+	KeyMap table.KeyMap
+	Styles TableStyles
+
+	columns []Column
+	rows    []Row
+	cursor  int
+	offset  int
+	width   int
+	height  int
+}
+
+func NewTable() Table {
+	return Table{
+		KeyMap: table.DefaultKeyMap(),
+		Styles: DefaultTableStyles(),
+	}
+}
+
+func (t *Table) SetColumns(columns []Column) {
+	t.columns = columns
+}
+
+func (t Table) Columns() []Column {
+	return t.columns
+}
+
+func (t *Table) SetRows(rows []Row) {
+	t.rows = rows
+	t.SetCursor(t.cursor)
+}
+
+func (t Table) Rows() []Row {
+	return t.rows
+}
+
+func (t *Table) SetWidth(width int) {
+	t.width = width
+}
+
+func (t Table) Width() int {
+	return t.width
+}
+
+// SetHeight sets the height of the whole table, including the header.
+func (t *Table) SetHeight(height int) {
+	t.height = height
+	t.SetCursor(t.cursor)
+}
+
+func (t Table) Height() int {
+	return t.height
+}
+
+func (t Table) Cursor() int {
+	return t.cursor
+}
+
+func (t *Table) SetCursor(cursor int) {
+	t.cursor = max(0, min(cursor, len(t.rows)-1))
+	t.scrollToCursor()
+}
+
+func (t Table) SelectedRow() Row {
+	if t.cursor < 0 || t.cursor >= len(t.rows) {
+		return nil
+	}
+	return t.rows[t.cursor]
+}
+
+func (t *Table) MoveUp(n int) {
+	t.SetCursor(t.cursor - n)
+}
+
+func (t *Table) MoveDown(n int) {
+	t.SetCursor(t.cursor + n)
+}
+
+func (t *Table) GotoTop() {
+	t.SetCursor(0)
+}
+
+func (t *Table) GotoBottom() {
+	t.SetCursor(len(t.rows) - 1)
+}
+
+func (t Table) headerHeight() int {
+	return lipgloss.Height(t.headersView())
+}
+
+func (t Table) visibleRowCount() int {
+	return max(1, t.height-t.headerHeight())
+}
+
+func (t *Table) scrollToCursor() {
+	visible := t.visibleRowCount()
+	if t.cursor < t.offset {
+		t.offset = t.cursor
+	}
+	if t.cursor >= t.offset+visible {
+		t.offset = t.cursor - visible + 1
+	}
+	t.offset = max(0, min(t.offset, len(t.rows)-visible))
+}
+
+// RowAt returns the index of the row shown on line y, counting from the top
+// of the header.
+func (t Table) RowAt(y int) (int, bool) {
+	line := y - t.headerHeight()
+	if line < 0 || line >= t.visibleRowCount() {
+		return 0, false
+	}
+	index := t.offset + line
+	if index >= len(t.rows) {
+		return 0, false
+	}
+	return index, true
+}
+
+// IsHeader reports whether line y belongs to the header.
+func (t Table) IsHeader(y int) bool {
+	return y >= 0 && y < t.headerHeight()
+}
+
+// ColumnAt returns the index of the column at x, counting from the left edge
+// of the table.
+func (t Table) ColumnAt(x int) (int, bool) {
+	if x < 0 {
+		return 0, false
+	}
+	frame := t.Styles.Header.GetHorizontalFrameSize()
+	for i, column := range t.columns {
+		if column.Width <= 0 {
+			continue
+		}
+		if x < column.Width+frame {
+			return i, true
+		}
+		x -= column.Width + frame
+	}
+	return 0, false
+}
+
+func (t Table) Update(msg tea.Msg) (Table, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		if msg.Action != tea.MouseActionPress {
+			break
+		}
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			t.MoveUp(1)
+		case tea.MouseButtonWheelDown:
+			t.MoveDown(1)
+		}
+	case tea.KeyMsg:
+		visible := t.visibleRowCount()
+		switch {
+		case key.Matches(msg, t.KeyMap.LineUp):
+			t.MoveUp(1)
+		case key.Matches(msg, t.KeyMap.LineDown):
+			t.MoveDown(1)
+		case key.Matches(msg, t.KeyMap.PageUp):
+			t.MoveUp(visible)
+		case key.Matches(msg, t.KeyMap.PageDown):
+			t.MoveDown(visible)
+		case key.Matches(msg, t.KeyMap.HalfPageUp):
+			t.MoveUp(visible / 2)
+		case key.Matches(msg, t.KeyMap.HalfPageDown):
+			t.MoveDown(visible / 2)
+		case key.Matches(msg, t.KeyMap.GotoTop):
+			t.GotoTop()
+		case key.Matches(msg, t.KeyMap.GotoBottom):
+			t.GotoBottom()
+		}
+	}
+	return t, nil
+}
+
+func (t Table) View() string {
+	lines := []string{t.headersView()}
+
+	visible := t.visibleRowCount()
+	for i := t.offset; i < min(t.offset+visible, len(t.rows)); i++ {
+		lines = append(lines, t.fitWidth(t.renderRow(i)))
+	}
+	for i := len(lines) - 1; i < visible; i++ {
+		lines = append(lines, strings.Repeat(" ", t.width))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (t Table) fitWidth(line string) string {
+	width := lipgloss.Width(line)
+	if width < t.width {
+		return line + strings.Repeat(" ", t.width-width)
+	}
+	return line
+}
+
+func (t Table) headersView() string {
+	headers := make([]string, 0, len(t.columns))
+	for _, column := range t.columns {
+		if column.Width <= 0 {
+			continue
+		}
+		title := padRight(runewidth.Truncate(column.Title, column.Width, "…"), column.Width)
+		headers = append(headers, t.Styles.Header.Render(title))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, headers...)
+}
+
+func (t Table) renderRow(index int) string {
+	base := lipgloss.NewStyle()
+	if index == t.cursor {
+		base = t.Styles.Selected
+	}
+	highlight := t.Styles.Highlight.Inherit(base)
+
+	b := strings.Builder{}
+	for i, column := range t.columns {
+		if column.Width <= 0 {
+			continue
+		}
+		cell := Cell{}
+		if i < len(t.rows[index]) {
+			cell = t.rows[index][i]
+		}
+
+		b.WriteString(base.Render(strings.Repeat(" ", t.Styles.Cell.GetPaddingLeft())))
+		if cell.Faint {
+			b.WriteString(renderCell(cell, column.Width, base.Faint(true), highlight))
+		} else {
+			b.WriteString(renderCell(cell, column.Width, base, highlight))
+		}
+		b.WriteString(base.Render(strings.Repeat(" ", t.Styles.Cell.GetPaddingRight())))
+	}
+	return b.String()
+}
+
+func renderCell(cell Cell, width int, base lipgloss.Style, highlight lipgloss.Style) string {
+	text := runewidth.Truncate(cell.Text, width, "…")
+	truncated := text != cell.Text
+
+	runes := []rune(text)
+	if truncated {
+		// never highlight the ellipsis
+		runes = runes[:len(runes)-1]
+	}
+
+	b := strings.Builder{}
+	segment := []rune{}
+	segmentHighlighted := false
+	flush := func() {
+		if len(segment) == 0 {
+			return
+		}
+		if segmentHighlighted {
+			b.WriteString(highlight.Render(string(segment)))
+		} else {
+			b.WriteString(base.Render(string(segment)))
+		}
+		segment = segment[:0]
+	}
+
+	for i, r := range runes {
+		isHighlighted := slices.Contains(cell.Highlights, i)
+		if isHighlighted != segmentHighlighted {
+			flush()
+			segmentHighlighted = isHighlighted
+		}
+		segment = append(segment, r)
+	}
+	flush()
+
+	if truncated {
+		b.WriteString(base.Render("…"))
+	}
+
+	if padding := width - runewidth.StringWidth(text); padding > 0 {
+		b.WriteString(base.Render(strings.Repeat(" ", padding)))
+	}
+
+	return b.String()
+}
+
+func padRight(text string, width int) string {
+	if padding := width - runewidth.StringWidth(text); padding > 0 {
+		return text + strings.Repeat(" ", padding)
+	}
+	return text
+}
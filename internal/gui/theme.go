@@ -0,0 +1,149 @@
+package gui
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme names the colors by what they are used for. Colors can be adaptive
+// so one theme works on both light and dark terminals.
+type Theme struct {
+	// titles, focused fields and buttons
+	Primary lipgloss.TerminalColor
+	// typed text, the spinner and other small highlights
+	Accent lipgloss.TerminalColor
+	Text   lipgloss.TerminalColor
+	Border lipgloss.TerminalColor
+	// the row under the cursor
+	SelectedText       lipgloss.TerminalColor
+	SelectedBackground lipgloss.TerminalColor
+	Success            lipgloss.TerminalColor
+	// search matches, queued work and filters
+	Warning lipgloss.TerminalColor
+	Error   lipgloss.TerminalColor
+	// Without colors the selection is shown by reversing the text
+	Monochrome bool
+}
+
+func adaptive(light, dark string) lipgloss.AdaptiveColor {
+	return lipgloss.AdaptiveColor{Light: light, Dark: dark}
+}
+
+// The default theme sticks to the 16 terminal colors so it follows the
+// terminal's own palette.
+var defaultTheme = Theme{
+	Primary:            lipgloss.Color("4"),
+	Accent:             lipgloss.Color("5"),
+	Text:               adaptive("0", "7"),
+	Border:             lipgloss.Color("8"),
+	SelectedText:       lipgloss.Color("12"),
+	SelectedBackground: adaptive("7", "0"),
+	Success:            lipgloss.Color("2"),
+	Warning:            lipgloss.Color("3"),
+	Error:              lipgloss.Color("9"),
+}
+
+type catppuccinFlavor struct {
+	text, overlay, surface, base, blue, mauve, green, yellow, red, lavender string
+}
+
+// https://github.com/catppuccin/catppuccin
+var (
+	latte     = catppuccinFlavor{"#4c4f69", "#9ca0b0", "#ccd0da", "#eff1f5", "#1e66f5", "#8839ef", "#40a02b", "#df8e1d", "#d20f39", "#7287fd"}
+	frappe    = catppuccinFlavor{"#c6d0f5", "#737994", "#414559", "#303446", "#8caaee", "#ca9ee6", "#a6d189", "#e5c890", "#e78284", "#babbf1"}
+	macchiato = catppuccinFlavor{"#cad3f5", "#6e738d", "#363a4f", "#24273a", "#8aadf4", "#c6a0f6", "#a6da95", "#eed49f", "#ed8796", "#b7bdf8"}
+	mocha     = catppuccinFlavor{"#cdd6f4", "#6c7086", "#313244", "#1e1e2e", "#89b4fa", "#cba6f7", "#a6e3a1", "#f9e2af", "#f38ba8", "#b4befe"}
+)
+
+// catppuccinTheme uses light for light terminals and dark for dark ones.
+func catppuccinTheme(light, dark catppuccinFlavor) Theme {
+	return Theme{
+		Primary:            adaptive(light.blue, dark.blue),
+		Accent:             adaptive(light.mauve, dark.mauve),
+		Text:               adaptive(light.text, dark.text),
+		Border:             adaptive(light.overlay, dark.overlay),
+		SelectedText:       adaptive(light.lavender, dark.lavender),
+		SelectedBackground: adaptive(light.surface, dark.surface),
+		Success:            adaptive(light.green, dark.green),
+		Warning:            adaptive(light.yellow, dark.yellow),
+		Error:              adaptive(light.red, dark.red),
+	}
+}
+
+var highContrastTheme = Theme{
+	Primary:            adaptive("4", "14"),
+	Accent:             adaptive("5", "13"),
+	Text:               adaptive("0", "15"),
+	Border:             adaptive("0", "15"),
+	SelectedText:       adaptive("15", "0"),
+	SelectedBackground: adaptive("0", "15"),
+	Success:            adaptive("2", "10"),
+	Warning:            adaptive("3", "11"),
+	Error:              adaptive("1", "9"),
+}
+
+var monochromeTheme = Theme{
+	Primary:            lipgloss.NoColor{},
+	Accent:             lipgloss.NoColor{},
+	Text:               lipgloss.NoColor{},
+	Border:             lipgloss.NoColor{},
+	SelectedText:       lipgloss.NoColor{},
+	SelectedBackground: lipgloss.NoColor{},
+	Success:            lipgloss.NoColor{},
+	Warning:            lipgloss.NoColor{},
+	Error:              lipgloss.NoColor{},
+	Monochrome:         true,
+}
+
+var themes = map[string]Theme{
+	"default":              defaultTheme,
+	"catppuccin":           catppuccinTheme(latte, mocha),
+	"catppuccin-latte":     catppuccinTheme(latte, latte),
+	"catppuccin-frappe":    catppuccinTheme(frappe, frappe),
+	"catppuccin-macchiato": catppuccinTheme(macchiato, macchiato),
+	"catppuccin-mocha":     catppuccinTheme(mocha, mocha),
+	"high-contrast":        highContrastTheme,
+	"monochrome":           monochromeTheme,
+}
+
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CurrentTheme is the theme every style is built from.
+var CurrentTheme = defaultTheme
+
+// SetTheme switches to the named theme, or the default one when it's empty.
+// NO_COLOR always wins. It reports false for unknown names.
+func SetTheme(name string) bool {
+	theme, ok := themes[strings.ToLower(name)]
+	if name == "" {
+		theme, ok = defaultTheme, true
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		theme = monochromeTheme
+	}
+	if ok || os.Getenv("NO_COLOR") != "" {
+		CurrentTheme = theme
+		applyTheme()
+	}
+	return ok
+}
+
+// SelectedStyle highlights the row under the cursor.
+func SelectedStyle() lipgloss.Style {
+	if CurrentTheme.Monochrome {
+		return lipgloss.NewStyle().Reverse(true)
+	}
+	return lipgloss.NewStyle().
+		Foreground(CurrentTheme.SelectedText).
+		Background(CurrentTheme.SelectedBackground)
+}
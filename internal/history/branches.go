@@ -0,0 +1,90 @@
+package history
+
+import (
+	"time"
+
+	"github.com/joshwrn/jira-branch/internal/utils"
+)
+
+const (
+	branchesFileName = "branch-history.json"
+	maxBranchEntries = 200
+)
+
+type BranchAction string
+
+const (
+	BranchCreated    BranchAction = "created"
+	BranchCheckedOut BranchAction = "checkout"
+)
+
+// BranchEntry is a branch the tool created or checked out.
+type BranchEntry struct {
+	IssueKey string       `json:"issueKey"`
+	Summary  string       `json:"summary"`
+	Branch   string       `json:"branch"`
+	Repo     string       `json:"repo"`
+	Action   BranchAction `json:"action"`
+	At       time.Time    `json:"at"`
+}
+
+type branchesFile struct {
+	Entries []BranchEntry `json:"entries"`
+}
+
+// LoadBranches returns the branch history, newest first.
+func LoadBranches() ([]BranchEntry, error) {
+	file := branchesFile{}
+	err := utils.ReadDataFile(branchesFileName, &file)
+	return file.Entries, err
+}
+
+func AddBranch(entry BranchEntry) error {
+	entries, err := LoadBranches()
+	if err != nil {
+		utils.Log.Error().Err(err).Msg("Failed to read branch history, starting a new one")
+		entries = nil
+	}
+
+	if entry.At.IsZero() {
+		entry.At = time.Now()
+	}
+	entries = append([]BranchEntry{entry}, entries...)
+	if len(entries) > maxBranchEntries {
+		entries = entries[:maxBranchEntries]
+	}
+
+	return utils.WriteDataFile(branchesFileName, branchesFile{Entries: entries})
+}
+
+// RecentBranches keeps the newest entry of every branch, newest first.
+func RecentBranches(entries []BranchEntry) []BranchEntry {
+	seen := map[string]bool{}
+	recent := []BranchEntry{}
+	for _, entry := range entries {
+		id := entry.Repo + "\x00" + entry.Branch
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		recent = append(recent, entry)
+	}
+	return recent
+}
+
+// RemoveBranch forgets a branch that was deleted.
+func RemoveBranch(repo string, branchName string) error {
+	entries, err := LoadBranches()
+	if err != nil {
+		return err
+	}
+
+	kept := []BranchEntry{}
+	for _, entry := range entries {
+		if entry.Repo != repo || entry.Branch != branchName {
+			kept = append(kept, entry)
+		}
+	}
+
+	return utils.WriteDataFile(branchesFileName, branchesFile{Entries: kept})
+}
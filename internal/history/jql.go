@@ -0,0 +1,42 @@
+package history
+
+import (
+	"slices"
+
+	"github.com/joshwrn/jira-branch/internal/utils"
+)
+
+const (
+	jqlFileName   = "jql-history.json"
+	maxJQLQueries = 50
+)
+
+type jqlFile struct {
+	Queries []string `json:"queries"`
+}
+
+// LoadJQL returns the recently searched JQL queries, newest first.
+func LoadJQL() ([]string, error) {
+	file := jqlFile{}
+	err := utils.ReadDataFile(jqlFileName, &file)
+	return file.Queries, err
+}
+
+// AddJQL moves jql to the front of the history and returns the new history.
+func AddJQL(jql string) ([]string, error) {
+	queries, err := LoadJQL()
+	if err != nil {
+		utils.Log.Error().Err(err).Msg("Failed to read JQL history, starting a new one")
+		queries = nil
+	}
+
+	queries = slices.DeleteFunc(queries, func(query string) bool {
+		return query == jql
+	})
+	queries = append([]string{jql}, queries...)
+	if len(queries) > maxJQLQueries {
+		queries = queries[:maxJQLQueries]
+	}
+
+	return queries, utils.WriteDataFile(jqlFileName, jqlFile{Queries: queries})
+}
@@ -0,0 +1,108 @@
+package jira
+
+import "strings"
+
+// https://developer.atlassian.com/cloud/jira/platform/apis/document/structure/
+type ADFNode struct {
+	Type    string         `json:"type"`
+	Version int            `json:"version,omitempty"`
+	Text    string         `json:"text,omitempty"`
+	Content []ADFNode      `json:"content,omitempty"`
+	Marks   []ADFMark      `json:"marks,omitempty"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+}
+
+type ADFMark struct {
+	Type string `json:"type"`
+}
+
+// TextToADF turns plain text into an Atlassian Document, one paragraph per
+// line. Text between backticks is formatted as code.
+func TextToADF(text string) ADFNode {
+	doc := ADFNode{Type: "doc", Version: 1, Content: []ADFNode{}}
+
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		paragraph := ADFNode{Type: "paragraph"}
+		if line != "" {
+			paragraph.Content = inlineADF(line)
+		}
+		doc.Content = append(doc.Content, paragraph)
+	}
+
+	return doc
+}
+
+func inlineADF(line string) []ADFNode {
+	parts := strings.Split(line, "`")
+	// an unmatched backtick is just a backtick
+	if len(parts)%2 == 0 {
+		return []ADFNode{{Type: "text", Text: line}}
+	}
+
+	nodes := []ADFNode{}
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		node := ADFNode{Type: "text", Text: part}
+		if i%2 == 1 {
+			node.Marks = []ADFMark{{Type: "code"}}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// ADFToText renders an Atlassian Document as plain text, one line per
+// paragraph, heading or list item.
+func ADFToText(node ADFNode) string {
+	b := strings.Builder{}
+	writeADFText(&b, node, "")
+	return strings.TrimSpace(b.String())
+}
+
+func writeADFText(b *strings.Builder, node ADFNode, indent string) {
+	switch node.Type {
+	case "text":
+		b.WriteString(node.Text)
+		return
+	case "hardBreak":
+		b.WriteString("\n" + indent)
+		return
+	case "mention", "emoji", "date", "status":
+		if text, ok := node.Attrs["text"].(string); ok {
+			b.WriteString(text)
+		}
+		return
+	case "inlineCard", "blockCard":
+		if url, ok := node.Attrs["url"].(string); ok {
+			b.WriteString(url)
+		}
+		return
+	case "listItem":
+		b.WriteString(indent + "• ")
+		for _, child := range node.Content {
+			if child.Type == "bulletList" || child.Type == "orderedList" {
+				writeADFText(b, child, indent+"  ")
+				continue
+			}
+			writeADFText(b, child, indent)
+		}
+		return
+	case "bulletList", "orderedList":
+		for _, child := range node.Content {
+			writeADFText(b, child, indent)
+		}
+		return
+	case "paragraph", "heading", "codeBlock", "blockquote", "panel", "rule":
+		for _, child := range node.Content {
+			writeADFText(b, child, indent)
+		}
+		b.WriteString("\n")
+		return
+	}
+
+	for _, child := range node.Content {
+		writeADFText(b, child, indent)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/zalando/go-keyring"
 )
@@ -15,6 +16,11 @@ type Credentials struct {
 	APIToken string `json:"api_token"`
 }
 
+// IssueURL is the web page of an issue.
+func (c Credentials) IssueURL(key string) string {
+	return fmt.Sprintf("%s/browse/%s", strings.TrimSuffix(c.JiraURL, "/"), key)
+}
+
 func StoreCredentials(credentials Credentials) error {
 	data, err := json.Marshal(credentials)
 	if err != nil {
@@ -39,27 +45,15 @@ func ClearCredentials() error {
 
 // https://developer.atlassian.com/cloud/jira/platform/rest/v3/api-group-myself/#api-rest-api-3-myself-get
 func ValidateCredentials(credentials Credentials) error {
-	client := newClient()
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/rest/api/3/myself", credentials.JiraURL), nil)
+	client := NewClient(credentials)
+	resp, err := client.makeRequest("GET", "myself", nil)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %v", err)
-	}
-
-	req.Header.Add("Authorization", "Basic "+createAuthHeader(credentials))
-	req.Header.Add("Accept", "application/json")
-
-	resp, err := client.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to connect to Jira: %v", err)
+		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusUnauthorized {
-		return fmt.Errorf("invalid credentials: check your email and API token")
-	}
-
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected response from Jira API: %d", resp.StatusCode)
+		return newResponseError(resp, "GET", "myself")
 	}
 
 	return nil
@@ -5,38 +5,38 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/joshwrn/jira-branch/internal/utils"
 )
 
+// Client talks to the Jira REST API with a fixed set of credentials, so the
+// keyring is only read once per session.
 type Client struct {
-	httpClient *http.Client
+	httpClient  *http.Client
+	credentials Credentials
 }
 
-func newClient() *Client {
+func NewClient(credentials Credentials) *Client {
 	return &Client{
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		credentials: credentials,
 	}
 }
 
-func (c *Client) createRequest(method, endpoint string, body io.Reader) (*http.Request, error) {
-	credentials, err := LoadCredentials()
-	if err != nil {
-		return nil, err
-	}
-
-	url, err := createJiraUrl(endpoint)
-	if err != nil {
-		return nil, err
-	}
+func (c *Client) Credentials() Credentials {
+	return c.credentials
+}
 
-	req, err := http.NewRequest(method, url, body)
+func (c *Client) createRequest(method, endpoint string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, c.createJiraUrl(endpoint), body)
 
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Add("Authorization", "Basic "+createAuthHeader(credentials))
+	req.Header.Add("Authorization", "Basic "+createAuthHeader(c.credentials))
 	req.Header.Add("Accept", "application/json")
 
 	if body != nil {
@@ -52,13 +52,29 @@ func (c *Client) makeRequest(method, endpoint string, body io.Reader) (*http.Res
 		return nil, err
 	}
 
-	return c.httpClient.Do(req)
+	return c.do(req, method, endpoint)
 }
 
-func createJiraUrl(endpoint string) (string, error) {
-	credentials, err := LoadCredentials()
+func (c *Client) do(req *http.Request, method, endpoint string) (*http.Response, error) {
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+
+	logEvent := utils.Log.Debug().
+		Str("method", method).
+		Str("endpoint", endpoint).
+		Dur("duration", time.Since(start))
+	if resp != nil {
+		logEvent = logEvent.Int("status_code", resp.StatusCode)
+	}
+	logEvent.Msg("Jira request")
+
 	if err != nil {
-		return "", err
+		return nil, newTransportError(err, method, endpoint)
 	}
-	return fmt.Sprintf("%s/rest/api/3/%s", credentials.JiraURL, endpoint), nil
+
+	return resp, nil
+}
+
+func (c *Client) createJiraUrl(endpoint string) string {
+	return fmt.Sprintf("%s/rest/api/3/%s", c.credentials.JiraURL, endpoint)
 }
@@ -0,0 +1,263 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/joshwrn/jira-branch/internal/utils"
+)
+
+type ErrorKind int
+
+const (
+	ErrorKindUnknown ErrorKind = iota
+	ErrorKindUnauthorized
+	ErrorKindForbidden
+	ErrorKindInvalidJQL
+	ErrorKindNotFound
+	ErrorKindSiteNotFound
+	ErrorKindRateLimited
+	ErrorKindServer
+	ErrorKindNetwork
+)
+
+// APIError is returned for any failed call to the Jira REST API. Transport
+// failures have a zero StatusCode and the underlying error in Err.
+type APIError struct {
+	StatusCode  int
+	Method      string
+	Endpoint    string
+	Query       string
+	Messages    []string
+	FieldErrors map[string]string
+	Retryable   bool
+	Err         error
+}
+
+// https://developer.atlassian.com/cloud/jira/platform/rest/v3/intro/#status-codes
+type errorResponse struct {
+	ErrorMessages []string          `json:"errorMessages"`
+	Errors        map[string]string `json:"errors"`
+}
+
+func newResponseError(resp *http.Response, method, endpoint string) *APIError {
+	bodyBytes, _ := io.ReadAll(resp.Body)
+
+	utils.Log.Error().
+		Int("status_code", resp.StatusCode).
+		Str("method", method).
+		Str("endpoint", endpoint).
+		Str("response_body", string(bodyBytes)).
+		Msg("Jira API request failed")
+
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Method:     method,
+		Endpoint:   endpoint,
+		Retryable: resp.StatusCode == http.StatusTooManyRequests ||
+			resp.StatusCode >= http.StatusInternalServerError,
+	}
+
+	var parsed errorResponse
+	if json.Unmarshal(bodyBytes, &parsed) == nil {
+		apiErr.Messages = parsed.ErrorMessages
+		apiErr.FieldErrors = parsed.Errors
+	}
+
+	return apiErr
+}
+
+func newTransportError(err error, method, endpoint string) *APIError {
+	utils.Log.Error().
+		Err(err).
+		Str("method", method).
+		Str("endpoint", endpoint).
+		Msg("Failed to reach Jira")
+
+	return &APIError{
+		Method:    method,
+		Endpoint:  endpoint,
+		Retryable: !isHostNotFound(err),
+		Err:       err,
+	}
+}
+
+// An unknown host points at a wrong Jira URL, while any other DNS failure is
+// usually just a missing network connection.
+func isHostNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+// IsNetworkError reports whether err means Jira could not be reached at all.
+func IsNetworkError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Err != nil
+}
+
+// IsUnsent reports whether err means the request never left the machine,
+// because the host couldn't be resolved or connected to. After any other
+// network error Jira may have received the request.
+func IsUnsent(err error) bool {
+	if !IsNetworkError(err) {
+		return false
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+func (e *APIError) Kind() ErrorKind {
+	if e.Err != nil {
+		if isHostNotFound(e.Err) {
+			return ErrorKindSiteNotFound
+		}
+		return ErrorKindNetwork
+	}
+
+	switch {
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrorKindUnauthorized
+	case e.StatusCode == http.StatusForbidden:
+		return ErrorKindForbidden
+	case e.StatusCode == http.StatusBadRequest && e.Query != "":
+		// Jira reports projects the user cannot browse as if they did not exist
+		for _, msg := range e.Messages {
+			if strings.Contains(msg, "for the field 'project'") {
+				return ErrorKindForbidden
+			}
+		}
+		return ErrorKindInvalidJQL
+	case e.StatusCode == http.StatusNotFound:
+		// A missing site answers with an HTML page instead of Jira's JSON errors
+		if len(e.Messages) == 0 && len(e.FieldErrors) == 0 {
+			return ErrorKindSiteNotFound
+		}
+		return ErrorKindNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrorKindRateLimited
+	case e.StatusCode >= http.StatusInternalServerError:
+		return ErrorKindServer
+	}
+
+	return ErrorKindUnknown
+}
+
+// Details returns the messages reported by Jira, including field errors.
+func (e *APIError) Details() []string {
+	details := append([]string{}, e.Messages...)
+
+	fields := make([]string, 0, len(e.FieldErrors))
+	for field := range e.FieldErrors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		details = append(details, fmt.Sprintf("%s: %s", field, e.FieldErrors[field]))
+	}
+
+	return details
+}
+
+func (e *APIError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("failed to connect to Jira: %v", e.Err)
+	}
+
+	var summary string
+	switch e.Kind() {
+	case ErrorKindUnauthorized:
+		summary = "authentication failed: check your email and API token"
+	case ErrorKindForbidden:
+		summary = "permission denied"
+	case ErrorKindInvalidJQL:
+		summary = "invalid JQL query"
+	case ErrorKindSiteNotFound:
+		summary = "Jira site not found"
+	case ErrorKindRateLimited:
+		summary = "rate limited by Jira"
+	default:
+		summary = "jira API error"
+	}
+
+	msg := fmt.Sprintf("%s (%d %s %s)", summary, e.StatusCode, e.Method, e.Endpoint)
+	if details := e.Details(); len(details) > 0 {
+		msg += ": " + strings.Join(details, "; ")
+	}
+	return msg
+}
+
+var (
+	jqlPositionRegex = regexp.MustCompile(`\(line \d+, character (\d+)\)`)
+	jqlQuotedRegex   = regexp.MustCompile(`'([^']+)'`)
+	jqlClauseRegex   = regexp.MustCompile(`(?i)\s+(?:and|or|order\s+by)\s+`)
+)
+
+// JQLClause returns the clause of the query that Jira complained about, or an
+// empty string if it cannot be determined.
+func (e *APIError) JQLClause() string {
+	if e.Query == "" {
+		return ""
+	}
+
+	clauses := splitJQLClauses(e.Query)
+
+	for _, msg := range e.Messages {
+		if match := jqlPositionRegex.FindStringSubmatch(msg); match != nil {
+			// Jira counts characters from 1
+			position, err := strconv.Atoi(match[1])
+			if err == nil {
+				for _, clause := range clauses {
+					if position-1 >= clause.start && position-1 <= clause.end {
+						return clause.text
+					}
+				}
+			}
+		}
+	}
+
+	for _, msg := range e.Messages {
+		for _, match := range jqlQuotedRegex.FindAllStringSubmatch(msg, -1) {
+			for _, clause := range clauses {
+				if strings.Contains(strings.ToLower(clause.text), strings.ToLower(match[1])) {
+					return clause.text
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+type jqlClause struct {
+	text  string
+	start int
+	end   int
+}
+
+func splitJQLClauses(query string) []jqlClause {
+	clauses := []jqlClause{}
+	start := 0
+	for _, sep := range jqlClauseRegex.FindAllStringIndex(query, -1) {
+		clauses = append(clauses, jqlClause{text: query[start:sep[0]], start: start, end: sep[0]})
+		start = sep[1]
+	}
+	clauses = append(clauses, jqlClause{text: query[start:], start: start, end: len(query)})
+	return clauses
+}
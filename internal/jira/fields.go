@@ -0,0 +1,80 @@
+package jira
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// FieldText renders the value of a field Jira returned as text. It handles
+// what custom fields usually hold: strings, numbers, options, users and lists
+// of those.
+func FieldText(raw json.RawMessage) string {
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return ""
+	}
+	return valueText(value)
+}
+
+func valueText(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]any:
+		for _, key := range []string{"displayName", "name", "value", "key"} {
+			if text, ok := v[key].(string); ok {
+				return text
+			}
+		}
+		return ""
+	case []any:
+		items := v
+		if sprints, ok := currentSprints(v); ok {
+			items = sprints
+		}
+		texts := []string{}
+		for _, item := range items {
+			if text := valueText(item); text != "" {
+				texts = append(texts, text)
+			}
+		}
+		return strings.Join(texts, ", ")
+	}
+	return ""
+}
+
+// currentSprints picks the sprints that aren't closed yet out of a sprint
+// field, or the last one when they all are. It reports false for lists that
+// aren't sprints.
+func currentSprints(values []any) ([]any, bool) {
+	if len(values) == 0 {
+		return nil, false
+	}
+
+	open := []any{}
+	for _, value := range values {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		state, ok := object["state"].(string)
+		if !ok {
+			return nil, false
+		}
+		if state != "closed" {
+			open = append(open, value)
+		}
+	}
+
+	if len(open) == 0 {
+		return values[len(values)-1:], true
+	}
+	return open, true
+}
@@ -0,0 +1,208 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/joshwrn/jira-branch/internal/utils"
+)
+
+type myselfResponse struct {
+	AccountID string `json:"accountId"`
+}
+
+// https://developer.atlassian.com/cloud/jira/platform/rest/v3/api-group-myself/#api-rest-api-3-myself-get
+func (c *Client) GetCurrentAccountID() (string, error) {
+	resp, err := c.makeRequest("GET", "myself", nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", newResponseError(resp, "GET", "myself")
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var result myselfResponse
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		return "", err
+	}
+
+	return result.AccountID, nil
+}
+
+type assignIssueBody struct {
+	AccountID string `json:"accountId"`
+}
+
+// https://developer.atlassian.com/cloud/jira/platform/rest/v3/api-group-issues/#api-rest-api-3-issue-issueidorkey-assignee-put
+func (c *Client) AssignIssue(issueKey string, accountID string) error {
+	body, err := json.Marshal(assignIssueBody{AccountID: accountID})
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("issue/%s/assignee", issueKey)
+	resp, err := c.makeRequest("PUT", endpoint, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return newResponseError(resp, "PUT", endpoint)
+	}
+
+	return nil
+}
+
+type addCommentBody struct {
+	Body ADFNode `json:"body"`
+}
+
+// https://developer.atlassian.com/cloud/jira/platform/rest/v3/api-group-issue-comments/#api-rest-api-3-issue-issueidorkey-comment-post
+func (c *Client) AddComment(issueKey string, comment ADFNode) error {
+	body, err := json.Marshal(addCommentBody{Body: comment})
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("issue/%s/comment", issueKey)
+	resp, err := c.makeRequest("POST", endpoint, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return newResponseError(resp, "POST", endpoint)
+	}
+
+	return nil
+}
+
+type addWorklogBody struct {
+	TimeSpentSeconds int      `json:"timeSpentSeconds"`
+	Started          string   `json:"started"`
+	Comment          *ADFNode `json:"comment,omitempty"`
+}
+
+// https://developer.atlassian.com/cloud/jira/platform/rest/v3/api-group-issue-worklogs/#api-rest-api-3-issue-issueidorkey-worklog-post
+func (c *Client) AddWorklog(issueKey string, timeSpent time.Duration, started time.Time, comment string) error {
+	worklog := addWorklogBody{
+		TimeSpentSeconds: int(timeSpent.Seconds()),
+		Started:          started.Format("2006-01-02T15:04:05.000-0700"),
+	}
+	if comment != "" {
+		adf := TextToADF(comment)
+		worklog.Comment = &adf
+	}
+
+	body, err := json.Marshal(worklog)
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("issue/%s/worklog", issueKey)
+	resp, err := c.makeRequest("POST", endpoint, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return newResponseError(resp, "POST", endpoint)
+	}
+
+	return nil
+}
+
+// IssueDetails is what the preview shows about an issue beyond the columns
+// of the list.
+type IssueDetails struct {
+	Key         string
+	Summary     string
+	Type        string
+	Status      string
+	Priority    string
+	Assignee    string
+	Reporter    string
+	Labels      []string
+	Description string
+	Links       []RemoteLink
+}
+
+var issueDetailFields = []string{
+	"summary", "status", "issuetype", "priority", "assignee", "reporter", "labels", "description",
+}
+
+// https://developer.atlassian.com/cloud/jira/platform/rest/v3/api-group-issues/#api-rest-api-3-issue-issueidorkey-get
+func (c *Client) GetIssueDetails(issueKey string) (IssueDetails, error) {
+	endpoint := fmt.Sprintf("issue/%s", issueKey)
+	req, err := c.createRequest("GET", endpoint, nil)
+	if err != nil {
+		return IssueDetails{}, err
+	}
+
+	q := req.URL.Query()
+	q.Add("fields", strings.Join(issueDetailFields, ","))
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.do(req, "GET", endpoint)
+	if err != nil {
+		return IssueDetails{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return IssueDetails{}, newResponseError(resp, "GET", endpoint)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return IssueDetails{}, err
+	}
+
+	var issue Issue
+	err = json.Unmarshal(body, &issue)
+	if err != nil {
+		return IssueDetails{}, err
+	}
+
+	details := IssueDetails{
+		Key:      issue.Key,
+		Summary:  issue.Fields.Summary,
+		Type:     issue.Fields.IssueType.Name,
+		Status:   issue.Fields.Status.Name,
+		Priority: issue.Fields.Priority.Name,
+		Assignee: issue.Fields.Assignee.DisplayName,
+		Reporter: issue.Fields.Reporter.DisplayName,
+		Labels:   issue.Fields.Labels,
+	}
+
+	// the description is an Atlassian Document, or null when it's empty
+	var description ADFNode
+	if raw, ok := issue.RawFields["description"]; ok && json.Unmarshal(raw, &description) == nil {
+		details.Description = ADFToText(description)
+	}
+
+	// the links are extra, the details are worth showing without them
+	links, err := c.GetRemoteLinks(issueKey)
+	if err != nil {
+		utils.Log.Error().Err(err).Str("key", issueKey).Msg("Failed to fetch remote links")
+	}
+	details.Links = links
+
+	return details, nil
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/joshwrn/jira-branch/internal/utils"
 )
@@ -23,24 +25,16 @@ type TransitionResponse struct {
 }
 
 // https://developer.atlassian.com/cloud/jira/platform/rest/v3/api-group-issues/#api-rest-api-3-issue-issueidorkey-transitions-get
-func GetInProgressTransition(issueKey string, credentials Credentials) (string, error) {
-	client := newClient()
-	resp, err := client.makeRequest(
-		"GET",
-		fmt.Sprintf("issue/%s/transitions", issueKey),
-		nil,
-	)
+func (c *Client) GetTransitionID(issueKey string, transitionName string) (string, error) {
+	endpoint := fmt.Sprintf("issue/%s/transitions", issueKey)
+	resp, err := c.makeRequest("GET", endpoint, nil)
 	if err != nil {
 		return "", err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusUnauthorized {
-		return "", fmt.Errorf("authentication failed: check your credentials")
-	}
-
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("jira API error: %d", resp.StatusCode)
+		return "", newResponseError(resp, "GET", endpoint)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -55,17 +49,17 @@ func GetInProgressTransition(issueKey string, credentials Credentials) (string,
 	}
 
 	for _, transition := range result.Transitions {
-		if transition.Name == "In Progress" {
+		if strings.EqualFold(transition.Name, transitionName) {
 			return transition.ID, nil
 		}
 	}
 
-	return "", fmt.Errorf("transition not found")
+	return "", fmt.Errorf("transition %q not found for %s", transitionName, issueKey)
 }
 
 // https://developer.atlassian.com/cloud/jira/platform/rest/v3/api-group-issues/#api-rest-api-3-issue-issueidorkey-transitions-post
-func MarkAsInProgress(credentials Credentials, issueKey string) error {
-	transitionId, err := GetInProgressTransition(issueKey, credentials)
+func (c *Client) TransitionIssue(issueKey string, transitionName string) error {
+	transitionId, err := c.GetTransitionID(issueKey, transitionName)
 	if err != nil {
 		return err
 	}
@@ -77,23 +71,15 @@ func MarkAsInProgress(credentials Credentials, issueKey string) error {
 		return err
 	}
 
-	client := newClient()
-	resp, err := client.makeRequest(
-		"POST",
-		fmt.Sprintf("issue/%s/transitions", issueKey),
-		bytes.NewBuffer(body),
-	)
+	endpoint := fmt.Sprintf("issue/%s/transitions", issueKey)
+	resp, err := c.makeRequest("POST", endpoint, bytes.NewBuffer(body))
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusUnauthorized {
-		return fmt.Errorf("authentication failed: check your credentials")
-	}
-
 	if resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("jira API error: %d", resp.StatusCode)
+		return newResponseError(resp, "POST", endpoint)
 	}
 
 	return nil
@@ -105,49 +91,57 @@ type JiraTicketsMsg struct {
 	Type    string
 	Status  string
 	Created string
+
+	Priority      string
+	Updated       string
+	Assignee      string
+	Reporter      string
+	Labels        []string
+	DueDate       string
+	Parent        string
+	ParentSummary string
+	ParentType    string
+	IsSubtask     bool
+	// Keys of the subtasks, in Jira's order
+	Subtasks []string
+	// Other requested fields by ID, such as custom fields, rendered as text
+	Fields map[string]string
 }
 
+// ticketFields are always fetched since searching, branch names and the tree
+// need them.
+var ticketFields = []string{"summary", "status", "issuetype", "created", "parent", "subtasks"}
+
 // https://developer.atlassian.com/cloud/jira/platform/rest/v3/api-group-issue-search/#api-rest-api-3-search-jql-get
-func GetJiraTickets(credentials Credentials) ([]JiraTicketsMsg, error) {
-	client := newClient()
-	req, err := client.createRequest("GET", "search/jql", nil)
+func (c *Client) GetJiraTickets(jql string, extraFields []string) ([]JiraTicketsMsg, error) {
+	req, err := c.createRequest("GET", "search/jql", nil)
 	if err != nil {
 		return []JiraTicketsMsg{}, err
 	}
 
-	config, err := utils.ReadConfigFile()
-	if err != nil {
-		utils.Log.Info().Err(err).Msg("Failed to read config file")
+	fields := append([]string{}, ticketFields...)
+	for _, field := range extraFields {
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
 	}
 
 	q := req.URL.Query()
-	jql := ""
-	if config.ProjectKey != "" {
-		jql = fmt.Sprintf("project = %s AND ", config.ProjectKey)
-	}
-	jql = jql + "assignee = currentUser() AND status != Done order by createdDate"
 	q.Add("jql", jql)
-	q.Add("fields", "summary,status,issuetype,assignee,created")
+	q.Add("fields", strings.Join(fields, ","))
 	q.Add("maxResults", "100")
 	req.URL.RawQuery = q.Encode()
 
-	resp, err := client.httpClient.Do(req)
+	resp, err := c.do(req, "GET", "search/jql")
 	if err != nil {
 		return []JiraTicketsMsg{}, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusUnauthorized {
-		return []JiraTicketsMsg{}, fmt.Errorf("authentication failed: check your credentials")
-	}
-
 	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		utils.Log.Error().
-			Int("status_code", resp.StatusCode).
-			Str("response_body", string(bodyBytes)).
-			Msg("Failed to get Jira tickets")
-		return []JiraTicketsMsg{}, fmt.Errorf("jira API error: %d", resp.StatusCode)
+		apiErr := newResponseError(resp, "GET", "search/jql")
+		apiErr.Query = jql
+		return []JiraTicketsMsg{}, apiErr
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -163,35 +157,125 @@ func GetJiraTickets(credentials Credentials) ([]JiraTicketsMsg, error) {
 
 	newChoices := []JiraTicketsMsg{}
 	for _, issue := range result.Issues {
-		newChoices = append(newChoices, JiraTicketsMsg{
-			Key:     issue.Key,
-			Type:    issue.Fields.IssueType.Name,
-			Summary: issue.Fields.Summary,
-			Status:  issue.Fields.Status.Name,
-			Created: issue.Fields.Created,
-		})
+		ticket := JiraTicketsMsg{
+			Key:           issue.Key,
+			Type:          issue.Fields.IssueType.Name,
+			Summary:       issue.Fields.Summary,
+			Status:        issue.Fields.Status.Name,
+			Created:       issue.Fields.Created,
+			Priority:      issue.Fields.Priority.Name,
+			Updated:       issue.Fields.Updated,
+			Assignee:      issue.Fields.Assignee.DisplayName,
+			Reporter:      issue.Fields.Reporter.DisplayName,
+			Labels:        issue.Fields.Labels,
+			DueDate:       issue.Fields.DueDate,
+			Parent:        issue.Fields.Parent.Key,
+			ParentSummary: issue.Fields.Parent.Fields.Summary,
+			ParentType:    issue.Fields.Parent.Fields.IssueType.Name,
+			IsSubtask:     issue.Fields.IssueType.Subtask,
+		}
+		for _, subtask := range issue.Fields.Subtasks {
+			ticket.Subtasks = append(ticket.Subtasks, subtask.Key)
+		}
+
+		for _, field := range extraFields {
+			if _, known := knownFields[field]; known {
+				continue
+			}
+			if raw, ok := issue.RawFields[field]; ok {
+				if ticket.Fields == nil {
+					ticket.Fields = map[string]string{}
+				}
+				ticket.Fields[field] = FieldText(raw)
+			}
+		}
+
+		newChoices = append(newChoices, ticket)
 	}
 
 	return newChoices, nil
 }
 
+func BuildJQL(config utils.JiraBranchConfig) string {
+	jql := ""
+	if config.ProjectKey != "" {
+		jql = fmt.Sprintf("project = %s AND ", config.ProjectKey)
+	}
+	return jql + "assignee = currentUser() AND status != Done order by createdDate"
+}
+
 type JiraSearchResult struct {
 	Issues []Issue `json:"issues"`
 	Total  int     `json:"total"`
 }
 
 type Issue struct {
-	Key    string `json:"key"`
-	Fields struct {
-		Summary string `json:"summary"`
-		Status  struct {
-			Name string `json:"name"`
-		} `json:"status"`
-		IssueType struct {
-			Name string `json:"name"`
-		} `json:"issuetype"`
-		Created string `json:"created"`
-	} `json:"fields"`
+	Key    string      `json:"key"`
+	Fields IssueFields `json:"fields"`
+	// The same fields undecoded, for the ones IssueFields doesn't know about
+	RawFields map[string]json.RawMessage `json:"-"`
+}
+
+func (i *Issue) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Key    string                     `json:"key"`
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	i.Key = raw.Key
+	i.RawFields = raw.Fields
+
+	fields, err := json.Marshal(raw.Fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(fields, &i.Fields)
+}
+
+type IssueFields struct {
+	Summary string `json:"summary"`
+	Status  struct {
+		Name string `json:"name"`
+	} `json:"status"`
+	IssueType struct {
+		Name    string `json:"name"`
+		Subtask bool   `json:"subtask"`
+	} `json:"issuetype"`
+	Created  string `json:"created"`
+	Updated  string `json:"updated"`
+	Priority struct {
+		Name string `json:"name"`
+	} `json:"priority"`
+	Assignee struct {
+		DisplayName string `json:"displayName"`
+	} `json:"assignee"`
+	Reporter struct {
+		DisplayName string `json:"displayName"`
+	} `json:"reporter"`
+	Labels  []string `json:"labels"`
+	DueDate string   `json:"duedate"`
+	Parent  struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Summary   string `json:"summary"`
+			IssueType struct {
+				Name string `json:"name"`
+			} `json:"issuetype"`
+		} `json:"fields"`
+	} `json:"parent"`
+	Subtasks []struct {
+		Key string `json:"key"`
+	} `json:"subtasks"`
+}
+
+// knownFields are the fields IssueFields decodes.
+var knownFields = map[string]struct{}{
+	"summary": {}, "status": {}, "issuetype": {}, "created": {}, "updated": {},
+	"priority": {}, "assignee": {}, "reporter": {}, "labels": {}, "duedate": {},
+	"parent": {}, "subtasks": {},
 }
 
 type Fields struct {
@@ -0,0 +1,22 @@
+package jira
+
+import (
+	"regexp"
+	"strings"
+)
+
+// jqlSyntax spots input that is already JQL: a comparison operator, or one of
+// the keywords that only make sense in a query.
+var jqlSyntax = regexp.MustCompile(`(?i)([=~<>]|\bin\s*\(|\bis\s+(not\s+)?(empty|null)\b|\border\s+by\b)`)
+
+// SearchToJQL turns what was typed into the JQL search into a query. Plain
+// words become a full text search, anything that looks like JQL is sent as is.
+func SearchToJQL(input string) string {
+	input = strings.TrimSpace(input)
+	if input == "" || jqlSyntax.MatchString(input) {
+		return input
+	}
+
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(input)
+	return `text ~ "` + escaped + `" order by updated DESC`
+}
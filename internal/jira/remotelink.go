@@ -0,0 +1,77 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+type RemoteLink struct {
+	ID     int `json:"id"`
+	Object struct {
+		URL   string `json:"url"`
+		Title string `json:"title"`
+	} `json:"object"`
+}
+
+// https://developer.atlassian.com/cloud/jira/platform/rest/v3/api-group-issue-remote-links/#api-rest-api-3-issue-issueidorkey-remotelink-get
+func (c *Client) GetRemoteLinks(issueKey string) ([]RemoteLink, error) {
+	endpoint := fmt.Sprintf("issue/%s/remotelink", issueKey)
+	resp, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newResponseError(resp, "GET", endpoint)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []RemoteLink
+	err = json.Unmarshal(body, &links)
+	if err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
+
+type addRemoteLinkBody struct {
+	// Jira updates the link with the same global ID instead of adding another
+	GlobalID string `json:"globalId"`
+	Object   struct {
+		URL   string `json:"url"`
+		Title string `json:"title"`
+	} `json:"object"`
+}
+
+// https://developer.atlassian.com/cloud/jira/platform/rest/v3/api-group-issue-remote-links/#api-rest-api-3-issue-issueidorkey-remotelink-post
+func (c *Client) AddRemoteLink(issueKey string, url string, title string) error {
+	link := addRemoteLinkBody{GlobalID: url}
+	link.Object.URL = url
+	link.Object.Title = title
+	body, err := json.Marshal(link)
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("issue/%s/remotelink", issueKey)
+	resp, err := c.makeRequest("POST", endpoint, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return newResponseError(resp, "POST", endpoint)
+	}
+
+	return nil
+}
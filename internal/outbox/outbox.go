@@ -0,0 +1,290 @@
+package outbox
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/joshwrn/jira-branch/internal/jira"
+	"github.com/joshwrn/jira-branch/internal/utils"
+)
+
+const outboxFileName = "outbox.json"
+
+type Kind string
+
+const (
+	KindTransition Kind = "transition"
+	KindAssign     Kind = "assign"
+	KindComment    Kind = "comment"
+	KindWorklog    Kind = "worklog"
+	KindRemoteLink Kind = "remoteLink"
+)
+
+// Operation is a Jira write that could not be sent yet and will be replayed
+// the next time Jira is reachable.
+type Operation struct {
+	ID       string `json:"id"`
+	Kind     Kind   `json:"kind"`
+	IssueKey string `json:"issueKey"`
+
+	Transition string `json:"transition,omitempty"`
+	// An empty AccountID assigns the issue to the signed in user
+	AccountID string        `json:"accountId,omitempty"`
+	Comment   string        `json:"comment,omitempty"`
+	TimeSpent time.Duration `json:"timeSpent,omitempty"`
+	Started   time.Time     `json:"started,omitzero"`
+	LinkURL   string        `json:"linkUrl,omitempty"`
+	LinkTitle string        `json:"linkTitle,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"lastError,omitempty"`
+	// Held operations are only sent again on request, e.g. because Jira
+	// rejected them
+	Held bool `json:"held,omitempty"`
+}
+
+func (op Operation) Description() string {
+	switch op.Kind {
+	case KindTransition:
+		return fmt.Sprintf("Move to %q", op.Transition)
+	case KindAssign:
+		if op.AccountID == "" {
+			return "Assign to me"
+		}
+		return fmt.Sprintf("Assign to %s", op.AccountID)
+	case KindComment:
+		return fmt.Sprintf("Comment %q", op.Comment)
+	case KindWorklog:
+		return fmt.Sprintf("Log %s", op.TimeSpent)
+	case KindRemoteLink:
+		return fmt.Sprintf("Link %s", op.LinkURL)
+	}
+	return string(op.Kind)
+}
+
+// isIdempotent reports whether sending op twice does no harm. Comments and
+// worklogs would be added twice.
+func (op Operation) isIdempotent() bool {
+	return op.Kind != KindComment && op.Kind != KindWorklog
+}
+
+// canResend reports whether op can be sent again after it failed with err
+// without risking a duplicate in Jira.
+func (op Operation) canResend(err error) bool {
+	return jira.IsUnsent(err) || (jira.IsNetworkError(err) && op.isIdempotent())
+}
+
+type outboxFile struct {
+	Operations []Operation `json:"operations"`
+}
+
+// mu serializes read-modify-write cycles on the outbox file, since replays
+// run in the background while new operations can still be queued.
+var mu sync.Mutex
+
+func Load() ([]Operation, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	return load()
+}
+
+func load() ([]Operation, error) {
+	file := outboxFile{}
+	err := utils.ReadDataFile(outboxFileName, &file)
+	for i := range file.Operations {
+		// operations queued before there were other kinds are transitions
+		if file.Operations[i].Kind == "" {
+			file.Operations[i].Kind = KindTransition
+		}
+	}
+	return file.Operations, err
+}
+
+func save(operations []Operation) error {
+	return utils.WriteDataFile(outboxFileName, outboxFile{Operations: operations})
+}
+
+func Enqueue(op Operation) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	operations, err := load()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	op.ID = fmt.Sprintf("%d", now.UnixNano())
+	op.CreatedAt = now
+	operations = append(operations, op)
+
+	utils.Log.Info().
+		Str("issue", op.IssueKey).
+		Str("kind", string(op.Kind)).
+		Msg("Queued Jira operation")
+
+	return save(operations)
+}
+
+func execute(client *jira.Client, op Operation) error {
+	switch op.Kind {
+	case KindTransition:
+		return client.TransitionIssue(op.IssueKey, op.Transition)
+	case KindAssign:
+		accountID := op.AccountID
+		if accountID == "" {
+			var err error
+			accountID, err = client.GetCurrentAccountID()
+			if err != nil {
+				return err
+			}
+		}
+		return client.AssignIssue(op.IssueKey, accountID)
+	case KindComment:
+		return client.AddComment(op.IssueKey, jira.TextToADF(op.Comment))
+	case KindWorklog:
+		return client.AddWorklog(op.IssueKey, op.TimeSpent, op.Started, op.Comment)
+	case KindRemoteLink:
+		return addRemoteLink(client, op)
+	}
+	return fmt.Errorf("unknown operation kind %q", op.Kind)
+}
+
+// addRemoteLink links the issue to op.LinkURL unless it already is.
+func addRemoteLink(client *jira.Client, op Operation) error {
+	links, err := client.GetRemoteLinks(op.IssueKey)
+	if err != nil {
+		return err
+	}
+	for _, link := range links {
+		if link.Object.URL == op.LinkURL {
+			return nil
+		}
+	}
+	return client.AddRemoteLink(op.IssueKey, op.LinkURL, op.LinkTitle)
+}
+
+// Send runs op right away and queues it instead when Jira can't be reached.
+// It reports whether the operation was queued.
+func Send(client *jira.Client, op Operation) (bool, error) {
+	err := execute(client, op)
+	if op.canResend(err) {
+		op.Attempts = 1
+		op.LastError = err.Error()
+		return true, Enqueue(op)
+	}
+	if jira.IsNetworkError(err) {
+		return false, fmt.Errorf("%w\n\nIt may have reached Jira anyway, check %s before trying again", err, op.IssueKey)
+	}
+	return false, err
+}
+
+type ReplayResult struct {
+	Sent   int
+	Failed int
+}
+
+// Replay sends the queued operations in order. It stops at the first network
+// failure, while operations that Jira rejects, or that may have reached it
+// anyway, are held with their error so they can be retried or dropped. Held
+// operations are skipped unless includeHeld is set, so a bad operation isn't
+// sent on every refresh.
+func Replay(client *jira.Client, includeHeld bool) (ReplayResult, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	result := ReplayResult{}
+
+	operations, err := load()
+	if err != nil {
+		return result, err
+	}
+	if len(operations) == 0 {
+		return result, nil
+	}
+
+	remaining := []Operation{}
+	for i, op := range operations {
+		if op.Held && !includeHeld {
+			remaining = append(remaining, op)
+			continue
+		}
+		err := execute(client, op)
+		if op.canResend(err) {
+			remaining = append(remaining, operations[i:]...)
+			break
+		}
+		if err != nil {
+			utils.Log.Error().
+				Err(err).
+				Str("issue", op.IssueKey).
+				Str("kind", string(op.Kind)).
+				Msg("Queued Jira operation failed")
+			op.Attempts++
+			op.LastError = err.Error()
+			op.Held = true
+			remaining = append(remaining, op)
+			result.Failed++
+			continue
+		}
+		result.Sent++
+	}
+
+	return result, save(remaining)
+}
+
+// Retry sends a single queued operation and removes it on success.
+func Retry(client *jira.Client, id string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	operations, err := load()
+	if err != nil {
+		return err
+	}
+
+	for i, op := range operations {
+		if op.ID != id {
+			continue
+		}
+
+		err := execute(client, op)
+		if err != nil {
+			operations[i].Attempts++
+			operations[i].LastError = err.Error()
+			operations[i].Held = !op.canResend(err)
+			if saveErr := save(operations); saveErr != nil {
+				return saveErr
+			}
+			return err
+		}
+
+		return save(append(operations[:i], operations[i+1:]...))
+	}
+
+	return fmt.Errorf("operation %s is no longer queued", id)
+}
+
+func Drop(id string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	operations, err := load()
+	if err != nil {
+		return err
+	}
+
+	for i, op := range operations {
+		if op.ID == id {
+			utils.Log.Info().
+				Str("issue", op.IssueKey).
+				Str("kind", string(op.Kind)).
+				Msg("Dropped queued Jira operation")
+			return save(append(operations[:i], operations[i+1:]...))
+		}
+	}
+
+	return nil
+}
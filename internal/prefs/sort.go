@@ -0,0 +1,50 @@
+package prefs
+
+import "github.com/joshwrn/jira-branch/internal/utils"
+
+const sortFileName = "sort.json"
+
+// Sort is the column a ticket list is ordered by. An empty Column keeps the
+// order Jira returned.
+type Sort struct {
+	Column     string `json:"column"`
+	Descending bool   `json:"descending"`
+}
+
+type sortFile struct {
+	Queries map[string]Sort `json:"queries"`
+}
+
+func readSorts() (sortFile, error) {
+	file := sortFile{Queries: map[string]Sort{}}
+	err := utils.ReadDataFile(sortFileName, &file)
+	if file.Queries == nil {
+		file.Queries = map[string]Sort{}
+	}
+	return file, err
+}
+
+// LoadSort returns the sort that was last picked for the query.
+func LoadSort(jql string) Sort {
+	file, err := readSorts()
+	if err != nil {
+		utils.Log.Error().Err(err).Msg("Failed to read sort preferences")
+	}
+	return file.Queries[jql]
+}
+
+func SaveSort(jql string, sort Sort) error {
+	file, err := readSorts()
+	if err != nil {
+		utils.Log.Error().Err(err).Msg("Failed to read sort preferences, starting new ones")
+		file = sortFile{Queries: map[string]Sort{}}
+	}
+
+	if sort.Column == "" {
+		delete(file.Queries, jql)
+	} else {
+		file.Queries[jql] = sort
+	}
+
+	return utils.WriteDataFile(sortFileName, file)
+}
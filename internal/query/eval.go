@@ -0,0 +1,222 @@
+package query
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type Match struct {
+	Score int
+	// Matched rune indices by field name
+	Highlights map[string][]int
+}
+
+// Subject is the thing a query is matched against.
+type Subject interface {
+	Text(field string) string
+	Time(field string) (time.Time, bool)
+	// MatchText matches a free text term, the way the plain search does
+	MatchText(term string) (Match, bool)
+}
+
+func (q Query) Match(subject Subject) (Match, bool) {
+	// the zero Query matches everything, like an empty search
+	if q.root == nil {
+		return Match{Highlights: map[string][]int{}}, true
+	}
+	return q.root.match(subject)
+}
+
+type node interface {
+	match(subject Subject) (Match, bool)
+}
+
+type andNode struct {
+	children []node
+}
+
+func (n andNode) match(subject Subject) (Match, bool) {
+	result := Match{Highlights: map[string][]int{}}
+	for _, child := range n.children {
+		match, ok := child.match(subject)
+		if !ok {
+			return Match{}, false
+		}
+		result.merge(match)
+	}
+	return result, true
+}
+
+type orNode struct {
+	children []node
+}
+
+func (n orNode) match(subject Subject) (Match, bool) {
+	best, found := Match{}, false
+	for _, child := range n.children {
+		match, ok := child.match(subject)
+		if ok && (!found || match.Score > best.Score) {
+			best, found = match, true
+		}
+	}
+	return best, found
+}
+
+type notNode struct {
+	child node
+}
+
+func (n notNode) match(subject Subject) (Match, bool) {
+	if _, ok := n.child.match(subject); ok {
+		return Match{}, false
+	}
+	return Match{}, true
+}
+
+type textNode struct {
+	term string
+}
+
+func (n textNode) match(subject Subject) (Match, bool) {
+	return subject.MatchText(n.term)
+}
+
+type fieldNode struct {
+	field string
+	value string
+}
+
+func (n fieldNode) match(subject Subject) (Match, bool) {
+	text := subject.Text(n.field)
+	lowerText := strings.ToLower(text)
+	lowerValue := strings.ToLower(n.value)
+
+	if strings.Contains(n.value, "*") {
+		if ok, _ := path.Match(lowerValue, lowerText); ok {
+			return Match{Highlights: map[string][]int{}}, true
+		}
+		return Match{}, false
+	}
+
+	index := strings.Index(lowerText, lowerValue)
+	if index == -1 {
+		return Match{}, false
+	}
+
+	start := utf8.RuneCountInString(lowerText[:index])
+	positions := []int{}
+	for i := range utf8.RuneCountInString(lowerValue) {
+		positions = append(positions, start+i)
+	}
+	return Match{Highlights: map[string][]int{n.field: positions}}, true
+}
+
+type timeComparison struct {
+	op string
+	// either an age relative to now, or an absolute date
+	age  time.Duration
+	date time.Time
+}
+
+type timeNode struct {
+	field      string
+	comparison timeComparison
+}
+
+func (n timeNode) match(subject Subject) (Match, bool) {
+	t, ok := subject.Time(n.field)
+	if !ok {
+		return Match{}, false
+	}
+
+	c := n.comparison
+	var matched bool
+	if c.date.IsZero() {
+		// "<7d" means less than 7 days old, so newer than the cutoff
+		cutoff := time.Now().Add(-c.age)
+		switch c.op {
+		case "<", "<=":
+			matched = !t.Before(cutoff)
+		case ">", ">=":
+			matched = !t.After(cutoff)
+		}
+	} else {
+		// compare calendar days in the query's zone, not the one Jira sent
+		t = t.In(c.date.Location())
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, c.date.Location())
+		switch c.op {
+		case "<":
+			matched = day.Before(c.date)
+		case "<=":
+			matched = !day.After(c.date)
+		case ">":
+			matched = day.After(c.date)
+		case ">=":
+			matched = !day.Before(c.date)
+		case "=":
+			matched = day.Equal(c.date)
+		}
+	}
+
+	if !matched {
+		return Match{}, false
+	}
+	return Match{Highlights: map[string][]int{}}, true
+}
+
+var ageUnits = map[string]time.Duration{
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+	"y": 365 * 24 * time.Hour,
+}
+
+// parseTimeComparison accepts an optional operator followed by an age like
+// 7d, 2w or 12h, or a date like 2024-01-31.
+func parseTimeComparison(value string) (timeComparison, error) {
+	c := timeComparison{}
+	for _, op := range []string{"<=", ">=", "<", ">", "="} {
+		if strings.HasPrefix(value, op) {
+			c.op = op
+			value = strings.TrimPrefix(value, op)
+			break
+		}
+	}
+
+	if date, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		c.date = date
+		if c.op == "" {
+			c.op = "="
+		}
+		return c, nil
+	}
+
+	if len(value) >= 2 {
+		unit, ok := ageUnits[value[len(value)-1:]]
+		amount, err := strconv.Atoi(value[:len(value)-1])
+		if ok && err == nil && amount >= 0 {
+			c.age = time.Duration(amount) * unit
+			switch c.op {
+			case "":
+				c.op = "<"
+			case "=":
+				return c, fmt.Errorf("use < or > with an age like %s", value)
+			}
+			return c, nil
+		}
+	}
+
+	return c, fmt.Errorf("expected an age like <7d or a date like 2024-01-31, got %q", value)
+}
+
+func (m *Match) merge(other Match) {
+	m.Score += other.Score
+	for field, positions := range other.Highlights {
+		m.Highlights[field] = append(m.Highlights[field], positions...)
+	}
+}
@@ -0,0 +1,299 @@
+package query
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+type FieldKind int
+
+const (
+	TextField FieldKind = iota
+	TimeField
+)
+
+type ParseError struct {
+	// Rune offset into the query where the problem starts
+	Pos     int
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s (at %d)", e.Message, e.Pos+1)
+}
+
+// Query is a parsed search. All of its terms have to match:
+//
+//	pay ref                  free text, fuzzy matched against every field
+//	status:"in review"       field contains the value
+//	key:PRJ-1*               field matches the glob
+//	created:<7d              time fields compare against an age or a date
+//	-type:bug                negation
+//	type:bug OR type:task    alternatives, grouped with parentheses
+type Query struct {
+	root node
+}
+
+// Parse parses input, accepting only the given fields in field:value terms.
+func Parse(input string, fields map[string]FieldKind) (Query, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return Query{}, err
+	}
+
+	p := parser{tokens: tokens, fields: fields, end: len([]rune(input))}
+	if len(tokens) == 0 {
+		return Query{root: andNode{}}, nil
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return Query{}, err
+	}
+	if !p.done() {
+		tok := p.peek()
+		if tok.kind == tokenRightParen {
+			return Query{}, &ParseError{Pos: tok.pos, Message: "unexpected ')'"}
+		}
+		return Query{}, &ParseError{Pos: tok.pos, Message: fmt.Sprintf("unexpected %q", tok.text)}
+	}
+
+	return Query{root: root}, nil
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenLeftParen
+	tokenRightParen
+	tokenOr
+	tokenNot
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+	// set for words that were quoted, so "OR" can be searched for
+	quoted bool
+	// rune index in text of the first unquoted ':', or -1
+	colon int
+}
+
+func tokenize(input string) ([]token, error) {
+	runes := []rune(input)
+	tokens := []token{}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLeftParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRightParen, text: ")", pos: i})
+			i++
+		case r == '|':
+			tokens = append(tokens, token{kind: tokenOr, text: "|", pos: i})
+			i++
+		case r == '-' && (i+1 >= len(runes) || !unicode.IsSpace(runes[i+1])):
+			tokens = append(tokens, token{kind: tokenNot, text: "-", pos: i})
+			i++
+		default:
+			start := i
+			word := []rune{}
+			quoted := false
+			colon := -1
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' {
+				if runes[i] != '"' {
+					if runes[i] == ':' && colon == -1 {
+						colon = len(word)
+					}
+					word = append(word, runes[i])
+					i++
+					continue
+				}
+				quoteStart := i
+				i++
+				for i < len(runes) && runes[i] != '"' {
+					word = append(word, runes[i])
+					i++
+				}
+				if i >= len(runes) {
+					return nil, &ParseError{Pos: quoteStart, Message: "missing closing quote"}
+				}
+				i++
+				quoted = true
+			}
+			text := string(word)
+			if text == "OR" && !quoted {
+				tokens = append(tokens, token{kind: tokenOr, text: text, pos: start})
+				continue
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: text, pos: start, quoted: quoted, colon: colon})
+		}
+	}
+
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	index  int
+	fields map[string]FieldKind
+	end    int
+}
+
+func (p *parser) done() bool {
+	return p.index >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.index]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.index]
+	p.index++
+	return tok
+}
+
+func (p *parser) parseOr() (node, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	alternatives := []node{first}
+	for !p.done() && p.peek().kind == tokenOr {
+		or := p.next()
+		if p.done() || p.peek().kind == tokenOr || p.peek().kind == tokenRightParen {
+			return nil, &ParseError{Pos: or.pos, Message: "OR needs a term on both sides"}
+		}
+		next, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		alternatives = append(alternatives, next)
+	}
+
+	if len(alternatives) == 1 {
+		return first, nil
+	}
+	return orNode{children: alternatives}, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	children := []node{}
+	for !p.done() {
+		kind := p.peek().kind
+		if kind == tokenOr || kind == tokenRightParen {
+			break
+		}
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+
+	if len(children) == 0 {
+		pos := p.end
+		if !p.done() {
+			pos = p.peek().pos
+		}
+		return nil, &ParseError{Pos: pos, Message: "expected a search term"}
+	}
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	return andNode{children: children}, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.peek().kind != tokenNot {
+		return p.parsePrimary()
+	}
+
+	not := p.next()
+	if p.done() || p.peek().kind == tokenOr || p.peek().kind == tokenRightParen {
+		return nil, &ParseError{Pos: not.pos, Message: "'-' needs a term to exclude"}
+	}
+	child, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return notNode{child: child}, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokenLeftParen:
+		if p.done() {
+			return nil, &ParseError{Pos: tok.pos, Message: "missing closing ')'"}
+		}
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.done() || p.peek().kind != tokenRightParen {
+			return nil, &ParseError{Pos: tok.pos, Message: "missing closing ')'"}
+		}
+		p.next()
+		return inner, nil
+	case tokenNot:
+		return nil, &ParseError{Pos: tok.pos, Message: "'-' can't be repeated"}
+	case tokenWord:
+		return p.parseTerm(tok)
+	}
+
+	return nil, &ParseError{Pos: tok.pos, Message: fmt.Sprintf("unexpected %q", tok.text)}
+}
+
+func (p *parser) parseTerm(tok token) (node, error) {
+	if tok.colon == -1 {
+		return textNode{term: tok.text}, nil
+	}
+
+	runes := []rune(tok.text)
+	name := strings.ToLower(string(runes[:tok.colon]))
+	value := string(runes[tok.colon+1:])
+	kind, ok := p.fields[name]
+	if !ok {
+		known := make([]string, 0, len(p.fields))
+		for field := range p.fields {
+			known = append(known, field)
+		}
+		slices.Sort(known)
+		return nil, &ParseError{
+			Pos: tok.pos,
+			Message: fmt.Sprintf(
+				"unknown field %q, try %s or quote the text",
+				name,
+				strings.Join(known, ", "),
+			),
+		}
+	}
+
+	valuePos := tok.pos + tok.colon + 1
+	if value == "" {
+		return nil, &ParseError{Pos: valuePos, Message: fmt.Sprintf("%s: needs a value", name)}
+	}
+
+	if kind == TimeField {
+		comparison, err := parseTimeComparison(value)
+		if err != nil {
+			return nil, &ParseError{Pos: valuePos, Message: err.Error()}
+		}
+		return timeNode{field: name, comparison: comparison}, nil
+	}
+
+	return fieldNode{field: name, value: value}, nil
+}
@@ -0,0 +1,240 @@
+package timetrack
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joshwrn/jira-branch/internal/git_utils"
+	"github.com/joshwrn/jira-branch/internal/utils"
+)
+
+const (
+	sessionsFileName = "time-sessions.json"
+	// logged sessions are only kept around for a while
+	loggedRetention = 60 * 24 * time.Hour
+)
+
+// Session is a stretch of time a ticket's branch was checked out.
+type Session struct {
+	IssueKey string    `json:"issueKey"`
+	Summary  string    `json:"summary"`
+	Branch   string    `json:"branch"`
+	Repo     string    `json:"repo"`
+	Start    time.Time `json:"start"`
+	// zero while the branch is still checked out
+	End    time.Time `json:"end,omitzero"`
+	Logged bool      `json:"logged,omitempty"`
+}
+
+func (s Session) IsOpen() bool {
+	return s.End.IsZero()
+}
+
+func (s Session) Duration(now time.Time) time.Duration {
+	if s.IsOpen() {
+		return now.Sub(s.Start)
+	}
+	return s.End.Sub(s.Start)
+}
+
+type sessionsFile struct {
+	Sessions []Session `json:"sessions"`
+}
+
+// mu serializes read-modify-write cycles on the sessions file.
+var mu sync.Mutex
+
+func Load() ([]Session, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	return load()
+}
+
+func load() ([]Session, error) {
+	file := sessionsFile{}
+	err := utils.ReadDataFile(sessionsFileName, &file)
+	return file.Sessions, err
+}
+
+func save(sessions []Session) error {
+	cutoff := time.Now().Add(-loggedRetention)
+	sessions = slices.DeleteFunc(sessions, func(s Session) bool {
+		return s.Logged && s.End.Before(cutoff)
+	})
+	return utils.WriteDataFile(sessionsFileName, sessionsFile{Sessions: sessions})
+}
+
+// Start begins a session for a branch that was just checked out. Only one
+// thing is worked on at a time, so any open session ends here.
+func Start(session Session) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	// saving over a file that couldn't be read would lose all its time
+	sessions, err := load()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i := range sessions {
+		if sessions[i].IsOpen() {
+			sessions[i].End = now
+		}
+	}
+	session.Start = now
+	session.End = time.Time{}
+	sessions = append(sessions, session)
+
+	return save(sessions)
+}
+
+// Reconcile ends the open sessions whose branch was switched away from
+// outside of the tool, at the time git recorded the switch.
+func Reconcile() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	sessions, err := load()
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for i, session := range sessions {
+		if !session.IsOpen() {
+			continue
+		}
+		current, err := git_utils.CurrentBranch(session.Repo)
+		if err == nil && current == session.Branch {
+			continue
+		}
+		end, ok := git_utils.SwitchedAwayAt(session.Repo, session.Branch, session.Start)
+		if !ok {
+			// the repository is gone or the reflog was cleaned up
+			end = time.Now()
+		}
+		sessions[i].End = end
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return save(sessions)
+}
+
+// Total is the time that wasn't logged yet for a ticket.
+type Total struct {
+	IssueKey string
+	Summary  string
+	Duration time.Duration
+	// start of the first session
+	Started   time.Time
+	Sessions  int
+	IsRunning bool
+	// when it was added up, time after this isn't part of Duration
+	At time.Time
+}
+
+// Totals adds up the sessions that weren't logged yet by ticket, the most
+// recently worked on ticket first.
+func Totals(sessions []Session, now time.Time) []Total {
+	totals := []Total{}
+	index := map[string]int{}
+	lastWorked := map[string]time.Time{}
+
+	for _, session := range sessions {
+		if session.Logged {
+			continue
+		}
+		i, ok := index[session.IssueKey]
+		if !ok {
+			i = len(totals)
+			index[session.IssueKey] = i
+			totals = append(totals, Total{
+				IssueKey: session.IssueKey,
+				Summary:  session.Summary,
+				Started:  session.Start,
+				At:       now,
+			})
+		}
+		total := &totals[i]
+		total.Duration += session.Duration(now)
+		total.Sessions++
+		total.IsRunning = total.IsRunning || session.IsOpen()
+		if session.Start.Before(total.Started) {
+			total.Started = session.Start
+		}
+		end := session.End
+		if session.IsOpen() {
+			end = now
+		}
+		if end.After(lastWorked[session.IssueKey]) {
+			lastWorked[session.IssueKey] = end
+		}
+	}
+
+	slices.SortStableFunc(totals, func(a, b Total) int {
+		return lastWorked[b.IssueKey].Compare(lastWorked[a.IssueKey])
+	})
+	return totals
+}
+
+// MarkLogged marks the time of a ticket up to cutoff as logged, which is
+// when the logged total was added up. Sessions running across the cutoff are
+// split, so the time after it is tracked again.
+func MarkLogged(issueKey string, cutoff time.Time) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	sessions, err := load()
+	if err != nil {
+		return err
+	}
+
+	for i, session := range sessions {
+		if session.IssueKey != issueKey || session.Logged || !session.Start.Before(cutoff) {
+			continue
+		}
+		if session.IsOpen() || session.End.After(cutoff) {
+			next := session
+			next.Start = cutoff
+			sessions = append(sessions, next)
+			sessions[i].End = cutoff
+		}
+		sessions[i].Logged = true
+	}
+
+	return save(sessions)
+}
+
+// FormatDuration shows a duration the way Jira does, like "1h 25m".
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dh %dm", hours, minutes)
+}
+
+// ParseDuration reads durations like "1h 25m", "90m" or "1.5h".
+func ParseDuration(text string) (time.Duration, error) {
+	compact := strings.Join(strings.Fields(strings.ToLower(text)), "")
+	d, err := time.ParseDuration(compact)
+	if err != nil || compact == "" {
+		return 0, fmt.Errorf("use hours and minutes, like 1h 30m")
+	}
+	if d < time.Minute {
+		return 0, fmt.Errorf("log at least a minute")
+	}
+	return d, nil
+}
@@ -0,0 +1,203 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type JiraBranchConfig struct {
+	ProjectKey string `json:"projectKey"`
+	// Color theme, e.g. "catppuccin" or "monochrome"
+	Theme string `json:"theme"`
+	// How often the ticket list is refreshed in the background, e.g. "5m"
+	RefreshInterval string `json:"refreshInterval"`
+	// Columns of the ticket list, e.g. ["key", "summary", "priority"]
+	Columns []ColumnConfig `json:"columns"`
+	// Custom field IDs differ between Jira sites
+	SprintField      string `json:"sprintField"`
+	StoryPointsField string `json:"storyPointsField"`
+	// Key overrides by action, e.g. {"refresh": "R", "open": ["o", "ctrl+o"]}
+	Keys map[string]KeyList `json:"keys"`
+	// Subtasks get their parent's branch instead of their own
+	ParentBranchForSubtasks bool `json:"parentBranchForSubtasks"`
+	// Post a comment on the issue when a branch is created, the default of the
+	// checkbox in the form
+	CommentOnBranch bool `json:"commentOnBranch"`
+	// e.g. "Started work on branch `{branch}` in repo `{repo}`"
+	BranchCommentTemplate string `json:"branchCommentTemplate"`
+	// Branch names longer than this get a warning in the form
+	MaxBranchLength int `json:"maxBranchLength"`
+	// Set to false to leave the mouse to the terminal, e.g. to select text
+	Mouse *bool `json:"mouse"`
+	// Push new branches and set their upstream, the default of the checkbox in
+	// the form
+	PushBranch bool `json:"pushBranch"`
+	// Remote branches are pushed to, defaults to "origin"
+	Remote string `json:"remote"`
+	// Body of pull requests, e.g. "{link}\n\n{description}"
+	PullRequestTemplate string `json:"pullRequestTemplate"`
+	// Link the issue to its pushed branch, "branch" or "compare"
+	RemoteLink string `json:"remoteLink"`
+	// Jira updates to make when the tool does something
+	Automation []AutomationRule `json:"automation"`
+	// Self-hosted forges by host, e.g. {"git.example.com": {"type": "gitlab"}}
+	Forges map[string]ForgeConfig `json:"forges"`
+}
+
+// AutomationRule moves the issue and comments on it when an event happens,
+// e.g. {"on": "pullRequestOpened", "transition": "In Review"}.
+type AutomationRule struct {
+	// "branchCreated", "branchPushed", "pullRequestOpened" or "branchDeleted"
+	On         string `json:"on"`
+	Transition string `json:"transition"`
+	// can use {key}, {summary}, {branch}, {repo} and {url}
+	Comment string `json:"comment"`
+}
+
+// ForgeConfig describes a forge that can't be told from its host name. The
+// URL templates can use {host}, {repo}, {branch} and {base}.
+type ForgeConfig struct {
+	// "github", "gitlab" or "bitbucket"
+	Type       string `json:"type"`
+	BranchURL  string `json:"branchUrl"`
+	CompareURL string `json:"compareUrl"`
+	// Base URL of the REST API, e.g. "https://git.example.com/api/v4"
+	APIURL string `json:"apiUrl"`
+	// Environment variable with the API token, defaults to GITHUB_TOKEN or
+	// GITLAB_TOKEN
+	TokenEnv string `json:"tokenEnv"`
+}
+
+// KeyList is one key or a list of keys.
+type KeyList []string
+
+func (k *KeyList) UnmarshalJSON(data []byte) error {
+	var key string
+	if err := json.Unmarshal(data, &key); err == nil {
+		*k = KeyList{key}
+		return nil
+	}
+
+	var keys []string
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return err
+	}
+	*k = keys
+	return nil
+}
+
+// ColumnConfig is either just the column name, or an object that also sets
+// the title and how much of the spare width the column gets.
+type ColumnConfig struct {
+	Field string `json:"field"`
+	Title string `json:"title"`
+	Width int    `json:"width"`
+}
+
+func (c *ColumnConfig) UnmarshalJSON(data []byte) error {
+	var field string
+	if err := json.Unmarshal(data, &field); err == nil {
+		*c = ColumnConfig{Field: field}
+		return nil
+	}
+
+	type plain ColumnConfig
+	return json.Unmarshal(data, (*plain)(c))
+}
+
+func (c JiraBranchConfig) GetSprintField() string {
+	if c.SprintField == "" {
+		return "customfield_10020"
+	}
+	return c.SprintField
+}
+
+func (c JiraBranchConfig) GetStoryPointsField() string {
+	if c.StoryPointsField == "" {
+		return "customfield_10016"
+	}
+	return c.StoryPointsField
+}
+
+func (c JiraBranchConfig) GetBranchCommentTemplate() string {
+	if c.BranchCommentTemplate == "" {
+		return "Started work on branch `{branch}` in repo `{repo}`"
+	}
+	return c.BranchCommentTemplate
+}
+
+func (c JiraBranchConfig) GetPullRequestTemplate() string {
+	if c.PullRequestTemplate == "" {
+		return "[{key}: {summary}]({link})\n\n{description}"
+	}
+	return c.PullRequestTemplate
+}
+
+func (c JiraBranchConfig) GetRemote() string {
+	if c.Remote == "" {
+		return "origin"
+	}
+	return c.Remote
+}
+
+func (c JiraBranchConfig) MouseEnabled() bool {
+	return c.Mouse == nil || *c.Mouse
+}
+
+// AutoRefreshInterval is 0 when auto refresh is off or the interval is
+// invalid, see ValidateRefreshInterval.
+func (c JiraBranchConfig) AutoRefreshInterval() time.Duration {
+	if c.RefreshInterval == "" {
+		return 0
+	}
+	interval, err := time.ParseDuration(c.RefreshInterval)
+	if err != nil || interval <= 0 {
+		return 0
+	}
+	// don't hammer the Jira API
+	return max(interval, 10*time.Second)
+}
+
+func (c JiraBranchConfig) ValidateRefreshInterval() error {
+	if c.RefreshInterval == "" {
+		return nil
+	}
+	interval, err := time.ParseDuration(c.RefreshInterval)
+	if err != nil || interval <= 0 {
+		return fmt.Errorf("invalid refresh interval %q, use something like 5m", c.RefreshInterval)
+	}
+	return nil
+}
+
+func readUserConfigFile() ([]byte, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filepath.Join(configDir, "jira-branch", "config.json"))
+}
+
+// ReadConfigFile reads the personal config from the user config dir and then
+// the repo's jira-branch.config.json on top of it, so repo settings win.
+func ReadConfigFile() (JiraBranchConfig, error) {
+	var config JiraBranchConfig
+
+	if file, err := readUserConfigFile(); err == nil {
+		if err := json.Unmarshal(file, &config); err != nil {
+			return config, fmt.Errorf("invalid user config: %w", err)
+		}
+	}
+
+	file, err := readFileFromGitRoot("jira-branch.config.json")
+	if err != nil {
+		return config, err
+	}
+	err = json.Unmarshal(file, &config)
+	if err != nil {
+		return config, err
+	}
+	return config, nil
+}
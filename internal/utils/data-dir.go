@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+func GetDataDir() (string, error) {
+	var baseDir string
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	switch runtime.GOOS {
+	case "windows":
+		appData := os.Getenv("LOCALAPPDATA")
+		if appData == "" {
+			appData = filepath.Join(homeDir, "AppData", "Local")
+		}
+		baseDir = filepath.Join(appData, "jira-branch")
+	case "darwin":
+		baseDir = filepath.Join(homeDir, "Library", "Application Support", "jira-branch")
+	default:
+		xdgDataHome := os.Getenv("XDG_DATA_HOME")
+		if xdgDataHome == "" {
+			xdgDataHome = filepath.Join(homeDir, ".local", "share")
+		}
+		baseDir = filepath.Join(xdgDataHome, "jira-branch")
+	}
+
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		return "", err
+	}
+
+	return baseDir, nil
+}
+
+// ReadDataFile decodes a JSON file from the data dir into v. A missing file
+// leaves v untouched and is not an error.
+func ReadDataFile(filename string, v any) error {
+	dataDir, err := GetDataDir()
+	if err != nil {
+		return err
+	}
+
+	file, err := os.ReadFile(filepath.Join(dataDir, filename))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(file, v)
+}
+
+// WriteDataFile encodes v as JSON into the data dir. The file is replaced
+// atomically so a crash never leaves it half written.
+func WriteDataFile(filename string, v any) error {
+	dataDir, err := GetDataDir()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dataDir, filename+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(dataDir, filename))
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
-	"runtime"
 
 	"github.com/rs/zerolog"
 )
@@ -26,36 +25,12 @@ func getLogFilePath() (string, error) {
 		return "app.log", nil
 	}
 
-	var baseDir string
-	var err error
-
-	homeDir, err := os.UserHomeDir()
+	dataDir, err := GetDataDir()
 	if err != nil {
 		return "", err
 	}
 
-	switch runtime.GOOS {
-	case "windows":
-		appData := os.Getenv("LOCALAPPDATA")
-		if appData == "" {
-			appData = filepath.Join(homeDir, "AppData", "Local")
-		}
-		baseDir = filepath.Join(appData, "jira-branch")
-	case "darwin":
-		baseDir = filepath.Join(homeDir, "Library", "Application Support", "jira-branch")
-	default:
-		xdgDataHome := os.Getenv("XDG_DATA_HOME")
-		if xdgDataHome == "" {
-			xdgDataHome = filepath.Join(homeDir, ".local", "share")
-		}
-		baseDir = filepath.Join(xdgDataHome, "jira-branch")
-	}
-
-	if err := os.MkdirAll(baseDir, 0755); err != nil {
-		return "", err
-	}
-
-	return filepath.Join(baseDir, "app.log"), nil
+	return filepath.Join(dataDir, "app.log"), nil
 }
 
 func Init() error {
@@ -1,14 +1,13 @@
 package utils
 
 import (
-	"encoding/json"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 )
 
-func getGitRoot() (string, error) {
+func GetGitRoot() (string, error) {
 	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
 	output, err := cmd.Output()
 	if err != nil {
@@ -18,7 +17,7 @@ func getGitRoot() (string, error) {
 }
 
 func readFileFromGitRoot(filename string) ([]byte, error) {
-	gitRoot, err := getGitRoot()
+	gitRoot, err := GetGitRoot()
 	if err != nil {
 		return nil, err
 	}
@@ -26,20 +25,3 @@ func readFileFromGitRoot(filename string) ([]byte, error) {
 	filePath := filepath.Join(gitRoot, filename)
 	return os.ReadFile(filePath)
 }
-
-type JiraBranchConfig struct {
-	ProjectKey string `json:"projectKey"`
-}
-
-func ReadConfigFile() (JiraBranchConfig, error) {
-	file, err := readFileFromGitRoot("jira-branch.config.json")
-	if err != nil {
-		return JiraBranchConfig{}, err
-	}
-	var config JiraBranchConfig
-	err = json.Unmarshal(file, &config)
-	if err != nil {
-		return JiraBranchConfig{}, err
-	}
-	return config, nil
-}
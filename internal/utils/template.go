@@ -0,0 +1,13 @@
+package utils
+
+import "strings"
+
+// ExpandTemplate fills in the {name} placeholders of a template. Unknown
+// placeholders are left as they are.
+func ExpandTemplate(template string, values map[string]string) string {
+	pairs := make([]string, 0, len(values)*2)
+	for name, value := range values {
+		pairs = append(pairs, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
@@ -5,12 +5,14 @@ import (
 	"time"
 )
 
-func FormatRelativeTime(timeStr string) string {
+func ParseJiraTime(timeStr string) (time.Time, error) {
 	layouts := []string{
 		"2006-01-02T15:04:05.000-0700",
 		"2006-01-02T15:04:05.000Z0700",
 		time.RFC3339Nano,
 		time.RFC3339,
+		// date fields like the due date
+		time.DateOnly,
 	}
 
 	var err error
@@ -22,6 +24,16 @@ func FormatRelativeTime(timeStr string) string {
 			break
 		}
 	}
+
+	return t, err
+}
+
+func FormatRelativeTime(timeStr string) string {
+	t, _ := ParseJiraTime(timeStr)
+	return FormatTimeAgo(t)
+}
+
+func FormatTimeAgo(t time.Time) string {
 	now := time.Now()
 	duration := now.Sub(t)
 